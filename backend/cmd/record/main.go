@@ -0,0 +1,91 @@
+// Command record exports games kept by the server's file store as plain text
+// game records, and imports records back into the store.
+//
+//	record export -dir DIR -game ID > game.txt
+//	record import -dir DIR [-id NEWID] game.txt
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/etanetan/up-and-down-the-river/backend/internal/game"
+	"github.com/google/uuid"
+)
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		usage()
+	}
+	switch os.Args[1] {
+	case "export":
+		exportGame(os.Args[2:])
+	case "import":
+		importGame(os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	log.Fatal("usage: record export -dir DIR -game ID\n       record import -dir DIR [-id NEWID] [FILE]")
+}
+
+func exportGame(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	dir := fs.String("dir", "", "game store directory")
+	id := fs.String("game", "", "ID of the game to export")
+	fs.Parse(args)
+	if *dir == "" || *id == "" {
+		usage()
+	}
+	store, err := game.NewFileStore(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	g, err := store.Get(*id)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := game.WriteRecord(os.Stdout, g); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func importGame(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	dir := fs.String("dir", "", "game store directory")
+	id := fs.String("id", "", "ID for the imported game (default: the one in the record)")
+	fs.Parse(args)
+	if *dir == "" || fs.NArg() > 1 {
+		usage()
+	}
+	var in io.Reader = os.Stdin
+	if fs.NArg() == 1 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		in = f
+	}
+	store, err := game.NewFileStore(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	g, err := game.ReadRecord(in, *id, func() string { return uuid.New().String() })
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := store.Put(g, 0); err != nil {
+		log.Fatalf("saving game %s: %v", g.ID, err)
+	}
+	fmt.Printf("imported game %s (%s)\n", g.ID, g.State)
+	for _, p := range g.Players {
+		fmt.Printf("%s\t%s\t%s\n", p.ID, p.DisplayName, p.Token)
+	}
+}
@@ -3,7 +3,9 @@ package main
 import (
 	"log"
 	"net/http"
+	"os"
 
+	"github.com/etanetan/up-and-down-the-river/backend/internal/game"
 	"github.com/etanetan/up-and-down-the-river/backend/internal/handlers"
 )
 
@@ -12,7 +14,8 @@ func withCORS(h http.HandlerFunc) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Access-Control-Allow-Origin", "*")
         w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Last-Event-ID, If-None-Match")
+	w.Header().Set("Access-Control-Expose-Headers", "ETag")
         // If it's an OPTIONS request, we can stop here.
         if r.Method == http.MethodOptions {
             w.WriteHeader(http.StatusOK)
@@ -23,14 +26,34 @@ func withCORS(h http.HandlerFunc) http.HandlerFunc {
 }
 
 func main() {
+    // Keep games on disk when a directory is given, so they survive restarts.
+    if dir := os.Getenv("GAME_STORE_DIR"); dir != "" {
+        store, err := game.NewFileStore(dir)
+        if err != nil {
+            log.Fatal(err)
+        }
+        game.Store = store
+    }
+    if err := handlers.ResumeReviews(); err != nil {
+        log.Fatal(err)
+    }
+
     // Wrap each handler with the CORS middleware.
     http.HandleFunc("/games/create", withCORS(handlers.CreateGameHandler))
     http.HandleFunc("/games/join", withCORS(handlers.JoinGameHandler))
     http.HandleFunc("/games/start", withCORS(handlers.StartGameHandler))
+    http.HandleFunc("/games/look", withCORS(handlers.LookHandler))
     http.HandleFunc("/games/bid", withCORS(handlers.BidHandler))
     http.HandleFunc("/games/play", withCORS(handlers.PlayHandler))
+    http.HandleFunc("/games/ack", withCORS(handlers.AckHandler))
     http.HandleFunc("/games/state", withCORS(handlers.GetGameStateHandler))
 		http.HandleFunc("/games/reset", withCORS(handlers.ResetGameHandler))
+    http.HandleFunc("/games/ws", handlers.WebSocketHandler)
+    http.HandleFunc("/games/events", withCORS(handlers.EventsHandler))
+    http.HandleFunc("/games/log", withCORS(handlers.LogHandler))
+    http.HandleFunc("/games/replay", withCORS(handlers.ReplayHandler))
+    http.HandleFunc("/games/export", withCORS(handlers.ExportHandler))
+    http.HandleFunc("/games/import", withCORS(handlers.ImportHandler))
 
     log.Println("Server started on :8080")
     log.Fatal(http.ListenAndServe(":8080", nil))
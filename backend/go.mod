@@ -2,4 +2,7 @@ module github.com/etanetan/up-and-down-the-river/backend
 
 go 1.23
 
-require github.com/google/uuid v1.6.0
+require (
+	github.com/google/uuid v1.6.0
+	github.com/gorilla/websocket v1.5.3
+)
@@ -0,0 +1,91 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Bid rules a game can be created with. Each forbids one bidder from making
+// the total of all bids equal the number of cards dealt, so that someone must
+// miss their bid.
+// BidRuleDealer restricts the dealer, except in one-card rounds. BidRuleAlways
+// restricts the dealer in every round, and BidRuleExceptPeak in every round
+// but those dealing the most cards. BidRuleLastBidder restricts whoever bids
+// last, except in one-card rounds. BidRuleOff allows any bid.
+// BidRuleRebid is the rule for sealed bids: when they add up to the cards
+// dealt, the dealer must bid again, except in one-card rounds.
+const (
+	BidRuleDealer     = "dealer"
+	BidRuleAlways     = "always"
+	BidRuleExceptPeak = "exceptPeak"
+	BidRuleLastBidder = "lastBidder"
+	BidRuleOff        = "off"
+	BidRuleRebid      = "rebid"
+)
+
+// checkBidRule reports what is wrong with the bid rule of a config, if
+// anything, given whether its bids are sealed.
+func checkBidRule(rule string, sealed bool) error {
+	switch rule {
+	case "", BidRuleOff:
+		return nil
+	case BidRuleDealer, BidRuleAlways, BidRuleExceptPeak, BidRuleLastBidder:
+		if sealed {
+			return fmt.Errorf("the %s bid rule cannot be used with sealed bids", rule)
+		}
+		return nil
+	case BidRuleRebid:
+		if !sealed {
+			return errors.New("the rebid bid rule only applies to sealed bids")
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown bid rule %q", rule)
+}
+
+// bidRestricted reports whether the bid rule applies to the player whose
+// turn it is to bid.
+func (g *Game) bidRestricted() bool {
+	round := g.CurrentRound
+	if g.Config.SealedBids {
+		return round.Rebid
+	}
+	bidder := round.BidOrder[round.CurrentBidTurn]
+	isDealer := bidder == g.Players[round.DealerIndex].ID
+	isLast := len(round.Bids) == len(g.Players)-1
+	switch g.Config.BidRule {
+	case BidRuleOff:
+		return false
+	case BidRuleAlways:
+		return isDealer
+	case BidRuleExceptPeak:
+		return isDealer && round.TotalCards != peakRound(g.RoundSequence)
+	case BidRuleLastBidder:
+		return isLast && round.TotalCards > 1
+	}
+	return isDealer && round.TotalCards > 1
+}
+
+// ForbiddenBid returns the bid the player whose turn it is may not make under
+// the game's bid rule, and false if there is none.
+// The caller must hold g's lock.
+func (g *Game) ForbiddenBid() (int, bool) {
+	if g.State != "bidding" || !g.bidRestricted() {
+		return 0, false
+	}
+	round := g.CurrentRound
+	forbidden := round.TotalCards - round.bidTotal()
+	if forbidden < 0 {
+		return 0, false
+	}
+	return forbidden, true
+}
+
+// peakRound returns the most cards dealt in any round of seq.
+func peakRound(seq []int) int {
+	peak := 0
+	for _, n := range seq {
+		peak = max(peak, n)
+	}
+	return peak
+}
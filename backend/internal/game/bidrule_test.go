@@ -0,0 +1,79 @@
+package game
+
+import "testing"
+
+// bidGame returns a three-player game dealt cards cards, with seat dealer
+// dealing and bids made in turn from seat2, then seat3, then seat1.
+func bidGame(rule string, sealed bool, dealer, cards int, bids []int) *Game {
+	g := &Game{
+		Config:        Config{BidRule: rule, SealedBids: sealed},
+		State:         "bidding",
+		RoundSequence: []int{1, 2, 3, 4, 5, 4, 3, 2, 1},
+		CurrentRound: &Round{
+			TotalCards:     cards,
+			DealerIndex:    dealer,
+			Bids:           make(map[string]int),
+			BidOrder:       []string{SeatHandle(1), SeatHandle(2), SeatHandle(0)},
+			CurrentBidTurn: len(bids),
+		},
+	}
+	for i := 0; i < 3; i++ {
+		g.Players = append(g.Players, &Player{ID: SeatHandle(i)})
+	}
+	for i, bid := range bids {
+		g.CurrentRound.Bids[g.CurrentRound.BidOrder[i]] = bid
+	}
+	return g
+}
+
+func TestForbiddenBid(t *testing.T) {
+	const none = -1
+	for _, tc := range []struct {
+		rule   string
+		dealer int
+		cards  int
+		bids   []int
+		want   int
+	}{
+		{BidRuleDealer, 0, 3, []int{1, 1}, 1},
+		{BidRuleDealer, 0, 3, []int{1}, none},
+		{BidRuleDealer, 0, 5, []int{2, 1}, 2},
+		{BidRuleDealer, 0, 1, []int{0, 0}, none},
+		{BidRuleDealer, 0, 3, []int{2, 2}, none},
+		{BidRuleDealer, 1, 3, nil, 3},
+		{BidRuleAlways, 0, 3, []int{1, 1}, 1},
+		{BidRuleAlways, 0, 5, []int{2, 1}, 2},
+		{BidRuleAlways, 0, 1, []int{0, 0}, 1},
+		{BidRuleAlways, 0, 1, []int{1}, none},
+		{BidRuleExceptPeak, 0, 3, []int{1, 1}, 1},
+		{BidRuleExceptPeak, 0, 1, []int{0, 1}, 0},
+		{BidRuleExceptPeak, 0, 5, []int{2, 1}, none},
+		{BidRuleLastBidder, 0, 3, []int{1, 1}, 1},
+		{BidRuleLastBidder, 0, 5, []int{2, 1}, 2},
+		{BidRuleLastBidder, 0, 1, []int{0, 0}, none},
+		// The last bidder is restricted even when someone else deals.
+		{BidRuleLastBidder, 1, 3, []int{0, 1}, 2},
+		{BidRuleLastBidder, 1, 3, nil, none},
+		{BidRuleOff, 0, 3, []int{1, 1}, none},
+		{BidRuleOff, 0, 1, []int{0, 0}, none},
+	} {
+		got, ok := bidGame(tc.rule, false, tc.dealer, tc.cards, tc.bids).ForbiddenBid()
+		if !ok {
+			got = none
+		}
+		if got != tc.want {
+			t.Errorf("%s rule, seat%d dealing %d cards, bids %v: forbidden %d, want %d", tc.rule, tc.dealer+1, tc.cards, tc.bids, got, tc.want)
+		}
+	}
+}
+
+func TestForbiddenBidOnRebid(t *testing.T) {
+	g := bidGame(BidRuleRebid, true, 0, 3, []int{1, 1})
+	if bid, ok := g.ForbiddenBid(); ok {
+		t.Fatalf("sealed bids: forbidden %d before any rebid", bid)
+	}
+	g.CurrentRound.Rebid = true
+	if bid, ok := g.ForbiddenBid(); !ok || bid != 1 {
+		t.Fatalf("rebid: forbidden %d (%t), want 1", bid, ok)
+	}
+}
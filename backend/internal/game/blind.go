@@ -0,0 +1,55 @@
+package game
+
+import "errors"
+
+// With Config.BlindBids, every hand is dealt face down: a player only sees
+// theirs once they look at it, which they may do at any time during the
+// bidding. Until then, on their turn, they may bid blind instead. A blind bid
+// that is made scores BlindBidMultiplier times what the scoring rule gives;
+// one that is missed costs BlindBidPenalty points on top.
+const (
+	BlindBidMultiplier = 2
+	BlindBidPenalty    = 20
+)
+
+// handHidden reports whether p has yet to look at the hand they were dealt.
+func (g *Game) handHidden(p *Player) bool {
+	return g.Config.BlindBids && g.State == "bidding" && !g.CurrentRound.Looked[p.ID]
+}
+
+func (g *Game) look(p *Player) ([]Event, error) {
+	if !g.Config.BlindBids {
+		return nil, errors.New("this game does not deal hands face down")
+	}
+	if g.State != "bidding" {
+		return nil, errors.New("not in bidding phase")
+	}
+	if !g.handHidden(p) {
+		return nil, errors.New("you have already looked at your hand")
+	}
+	g.CurrentRound.Looked[p.ID] = true
+	return []Event{{Type: EventHandSeen, PlayerID: p.ID}}, nil
+}
+
+// checkBlind reports why p may not make a bid that is blind or not as asked,
+// if they may not.
+func (g *Game) checkBlind(p *Player, blind bool) error {
+	switch {
+	case blind && !g.Config.BlindBids:
+		return errors.New("this game does not allow blind bids")
+	case blind && !g.handHidden(p):
+		return errors.New("you cannot bid blind after looking at your hand")
+	case !blind && g.handHidden(p):
+		return errors.New("look at your hand before bidding, or bid blind")
+	}
+	return nil
+}
+
+// blindScore returns what a blind bid scores, given what the scoring rule
+// gave it and whether it was made.
+func blindScore(score int, made bool) int {
+	if made {
+		return score * BlindBidMultiplier
+	}
+	return score - BlindBidPenalty
+}
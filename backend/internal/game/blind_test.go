@@ -0,0 +1,24 @@
+package game
+
+import "testing"
+
+func TestBlindScore(t *testing.T) {
+	for _, tc := range []struct {
+		score int
+		made  bool
+		want  int
+	}{
+		{19, true, 19 * BlindBidMultiplier},
+		{10, true, 10 * BlindBidMultiplier},
+		{50, true, 50 * BlindBidMultiplier},
+		{0, false, -BlindBidPenalty},
+		{-2, false, -2 - BlindBidPenalty},
+		{-40, false, -40 - BlindBidPenalty},
+		// A missed bid that still scores keeps its points less the penalty.
+		{3, false, 3 - BlindBidPenalty},
+	} {
+		if got := blindScore(tc.score, tc.made); got != tc.want {
+			t.Errorf("blind bid scoring %d, made %t: got %d, want %d", tc.score, tc.made, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var rankNames = map[int]string{11: "J", 12: "Q", 13: "K", 14: "A", 15: "JK2", 16: "JK1"}
+
+var suitLetters = map[string]string{"spades": "S", "hearts": "H", "diamonds": "D", "clubs": "C"}
+
+// String returns c in short notation: rank then suit letter, as in 2S, 10H,
+// QD or AC, with the jokers written JK1 (high) and JK2 (low).
+// A card with an unknown suit is written with "?" for its suit letter.
+func (c Card) String() string {
+	if c.Rank > 14 {
+		if name, ok := rankNames[c.Rank]; ok {
+			return name
+		}
+	}
+	rank, ok := rankNames[c.Rank]
+	if !ok {
+		rank = strconv.Itoa(c.Rank)
+	}
+	suit, ok := suitLetters[strings.ToLower(c.Suit)]
+	if !ok {
+		suit = "?"
+	}
+	return rank + suit
+}
+
+// ParseCard reads a card in the notation written by Card.String.
+// Letters may be in either case.
+func ParseCard(s string) (Card, error) {
+	text := strings.ToUpper(strings.TrimSpace(s))
+	switch text {
+	case "JK1":
+		return Card{Suit: "spades", Rank: int(Joker1)}, nil
+	case "JK2":
+		return Card{Suit: "spades", Rank: int(Joker2)}, nil
+	}
+	if len(text) < 2 {
+		return Card{}, fmt.Errorf("invalid card %q", s)
+	}
+	var suit string
+	for name, letter := range suitLetters {
+		if text[len(text)-1:] == letter {
+			suit = name
+		}
+	}
+	if suit == "" {
+		return Card{}, fmt.Errorf("invalid card %q", s)
+	}
+	rankText := text[:len(text)-1]
+	for rank, name := range rankNames {
+		if name == rankText && rank <= int(Ace) {
+			return Card{Suit: suit, Rank: rank}, nil
+		}
+	}
+	rank, err := strconv.Atoi(rankText)
+	if err != nil || rank < int(Two) || rank > int(Ten) {
+		return Card{}, fmt.Errorf("invalid card %q", s)
+	}
+	return Card{Suit: suit, Rank: rank}, nil
+}
+
+// UnmarshalJSON accepts a card either as a {"suit", "rank"} object or as a
+// string in short notation, such as "10H" or "JK1".
+func (c *Card) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		card, err := ParseCard(text)
+		if err != nil {
+			return err
+		}
+		*c = card
+		return nil
+	}
+	type object Card
+	return json.Unmarshal(data, (*object)(c))
+}
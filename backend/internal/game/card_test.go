@@ -0,0 +1,86 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseCard(t *testing.T) {
+	for _, tc := range []struct {
+		text string
+		want Card
+	}{
+		{"2S", Card{Suit: "spades", Rank: 2}},
+		{"9C", Card{Suit: "clubs", Rank: 9}},
+		{"10H", Card{Suit: "hearts", Rank: 10}},
+		{"10h", Card{Suit: "hearts", Rank: 10}},
+		{"JD", Card{Suit: "diamonds", Rank: 11}},
+		{"QD", Card{Suit: "diamonds", Rank: 12}},
+		{"kc", Card{Suit: "clubs", Rank: 13}},
+		{"AC", Card{Suit: "clubs", Rank: 14}},
+		{" AS ", Card{Suit: "spades", Rank: 14}},
+		{"JK1", Card{Suit: "spades", Rank: 16}},
+		{"jk2", Card{Suit: "spades", Rank: 15}},
+	} {
+		got, err := ParseCard(tc.text)
+		if err != nil {
+			t.Errorf("ParseCard(%q): %v", tc.text, err)
+			continue
+		}
+		if !CardEquals(got, tc.want) {
+			t.Errorf("ParseCard(%q) = %+v, want %+v", tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestParseCardErrors(t *testing.T) {
+	for _, text := range []string{"", "H", "1H", "11H", "0S", "10", "10X", "ZS", "JK", "JK3", "JK1S", "AH2"} {
+		if c, err := ParseCard(text); err == nil {
+			t.Errorf("ParseCard(%q) = %+v, want an error", text, c)
+		}
+	}
+}
+
+func TestCardStringRoundTrip(t *testing.T) {
+	for _, jokers := range []string{JokersNone, JokersTrump} {
+		for _, c := range CreateDeck(1, jokers) {
+			got, err := ParseCard(c.String())
+			if err != nil {
+				t.Errorf("ParseCard(%q): %v", c.String(), err)
+				continue
+			}
+			if !CardEquals(got, c) {
+				t.Errorf("%+v is written %q, which reads back as %+v", c, c.String(), got)
+			}
+		}
+	}
+}
+
+func TestCardUnmarshalJSON(t *testing.T) {
+	for _, tc := range []struct {
+		data string
+		want Card
+	}{
+		{`"10H"`, Card{Suit: "hearts", Rank: 10}},
+		{`"qs"`, Card{Suit: "spades", Rank: 12}},
+		{`"JK1"`, Card{Suit: "spades", Rank: 16}},
+		{`"JK2"`, Card{Suit: "spades", Rank: 15}},
+		{`{"suit":"hearts","rank":10}`, Card{Suit: "hearts", Rank: 10}},
+		{`{"suit":"spades","rank":16}`, Card{Suit: "spades", Rank: 16}},
+	} {
+		var got Card
+		if err := json.Unmarshal([]byte(tc.data), &got); err != nil {
+			t.Errorf("unmarshal %s: %v", tc.data, err)
+			continue
+		}
+		if !CardEquals(got, tc.want) {
+			t.Errorf("unmarshal %s = %+v, want %+v", tc.data, got, tc.want)
+		}
+	}
+	for _, data := range []string{`"1H"`, `"JK3"`, `""`, `12`} {
+		var c Card
+		if err := json.Unmarshal([]byte(data), &c); err == nil {
+			t.Errorf("unmarshal %s = %+v, want an error", data, c)
+		}
+	}
+}
@@ -0,0 +1,709 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// MaxPlayers is the largest number of seats at one table, and PlayersPerDeck
+// how many of them each deck in play makes room for.
+const (
+	MaxPlayers     = 10
+	PlayersPerDeck = 6
+)
+
+// MaxDecks is the most decks a game can shuffle together.
+const MaxDecks = 3
+
+// Action types accepted by Apply.
+const (
+	ActionJoin    = "join"
+	ActionStart   = "start"
+	ActionBid     = "bid"
+	ActionPlay    = "play"
+	ActionAck     = "ack"
+	ActionAdvance = "advance"
+	ActionReset   = "reset"
+	ActionLook    = "look"
+)
+
+// Action is one move applied to a game.
+// PlayerID is the public seat handle of the player making the move; it is
+// ignored for join, which takes a new seat, and for advance, which ends a
+// review on behalf of the whole table. Only the fields relevant to Type are used;
+// Blind marks a bid made without looking at the hand.
+// Seed drives every random choice the action makes, such as shuffling; Apply
+// picks one if it is zero. Time is when the action was made, for the log.
+type Action struct {
+	Type        string    `json:"type"`
+	PlayerID    string    `json:"playerId,omitempty"`
+	DisplayName string    `json:"displayName,omitempty"`
+	Token       string    `json:"token,omitempty"`
+	Bid         int       `json:"bid,omitempty"`
+	Blind       bool      `json:"blind,omitempty"`
+	Card        Card      `json:"card,omitempty"`
+	Seed        int64     `json:"seed,omitempty"`
+	Time        time.Time `json:"-"`
+}
+
+// LogEntry is one accepted action in a game's log.
+// Version is the game version the action produced.
+type LogEntry struct {
+	Version int       `json:"version"`
+	Time    time.Time `json:"time"`
+	Action  Action    `json:"action"`
+}
+
+// Engine is the rules of the game, free of any transport or timing.
+// *Game implements it; handlers, bots and simulators drive a game only through it.
+type Engine interface {
+	// Apply validates a and, if it is allowed, changes the game accordingly
+	// and returns the events describing the change.
+	Apply(a Action) ([]Event, error)
+	// LegalActions returns the moves the given seat may make right now.
+	LegalActions(seat string) []Action
+}
+
+// DefaultReviewDelayMs is how long a review lasts when a game does not choose.
+const DefaultReviewDelayMs = 2000
+
+// Config holds the options a game is created with.
+// The zero value of every option is a usable default.
+type Config struct {
+	ID string `json:"id"`
+	// MaxCards caps the number of cards dealt in the largest round; zero or
+	// a value above what the deck allows means as many as possible.
+	MaxCards int `json:"maxCards"`
+	// ReviewDelayMs is how long a trick or round review lasts unless every
+	// seat acknowledges it first; zero means DefaultReviewDelayMs and a
+	// negative value waits for the acknowledgements alone.
+	ReviewDelayMs int `json:"reviewDelayMs"`
+	// TrumpRule is one of the Trump rules, TrumpFixed if empty.
+	TrumpRule string `json:"trumpRule"`
+	// TrumpSuit is the suit TrumpRule starts from, DefaultTrumpSuit if empty.
+	TrumpSuit string `json:"trumpSuit"`
+	// Jokers is one of the joker options, JokersTrump if empty.
+	Jokers string `json:"jokers"`
+	// Scoring is the name of one of the ScoringRules, DefaultScoring if
+	// empty, or ScoringFormula to score with Formula.
+	Scoring string `json:"scoring"`
+	// Formula is the scoring formula; given alone it implies ScoringFormula.
+	Formula string `json:"formula,omitempty"`
+	// Sequence is one of the round sequence shapes, SequenceUpDown if empty.
+	Sequence string `json:"sequence"`
+	// FixedRounds and Rounds configure the sequence shapes that need them.
+	FixedRounds int   `json:"fixedRounds,omitempty"`
+	Rounds      []int `json:"rounds,omitempty"`
+	// BidRule is one of the bid rules, BidRuleDealer if empty, or
+	// BidRuleRebid under SealedBids.
+	BidRule string `json:"bidRule"`
+	// LeadRule is one of the lead rules, LeadHighestBidder if empty.
+	LeadRule string `json:"leadRule"`
+	// Decks is how many decks are shuffled together, one if zero.
+	Decks int `json:"decks"`
+	// Ties is one of the tie rules for identical cards, TiesFirstPlayed if empty.
+	Ties string `json:"ties"`
+	// BreakTrump keeps trump from being led until it has been broken.
+	BreakTrump bool `json:"breakTrump"`
+	// BlindBids deals hands face down so players may bid before looking.
+	BlindBids bool `json:"blindBids"`
+	// SealedBids has everyone bid at once, hidden until all bids are in.
+	SealedBids bool `json:"sealedBids"`
+	// Seed, when not zero, decides every deal of the game.
+	Seed int64 `json:"seed,string,omitempty"`
+}
+
+// Validate reports what is wrong with cfg, if anything.
+func (cfg Config) Validate() error {
+	if err := checkTrumpRule(cfg.TrumpRule, cfg.TrumpSuit); err != nil {
+		return err
+	}
+	if err := checkJokers(cfg.Jokers); err != nil {
+		return err
+	}
+	if err := checkScoring(cfg.Scoring, cfg.Formula); err != nil {
+		return err
+	}
+	if err := checkSequence(cfg); err != nil {
+		return err
+	}
+	if err := checkBidRule(cfg.BidRule, cfg.SealedBids); err != nil {
+		return err
+	}
+	if err := checkLeadRule(cfg.LeadRule); err != nil {
+		return err
+	}
+	if cfg.Decks < 0 || cfg.Decks > MaxDecks {
+		return fmt.Errorf("decks must be between 1 and %d", MaxDecks)
+	}
+	return checkTies(cfg.Ties)
+}
+
+// withDefaults returns cfg with the options it leaves empty filled in,
+// including options added since a stored game was created.
+func (cfg Config) withDefaults() Config {
+	if cfg.TrumpRule == "" {
+		cfg.TrumpRule = TrumpFixed
+	}
+	if cfg.TrumpSuit == "" {
+		cfg.TrumpSuit = DefaultTrumpSuit
+	}
+	cfg.TrumpSuit = strings.ToLower(cfg.TrumpSuit)
+	if cfg.Jokers == "" {
+		cfg.Jokers = JokersTrump
+	}
+	if cfg.Scoring == "" && cfg.Formula != "" {
+		cfg.Scoring = ScoringFormula
+	} else if cfg.Scoring == "" {
+		cfg.Scoring = DefaultScoring
+	}
+	if cfg.Sequence == "" {
+		cfg.Sequence = SequenceUpDown
+	}
+	if cfg.BidRule == "" {
+		cfg.BidRule = BidRuleDealer
+		if cfg.SealedBids {
+			cfg.BidRule = BidRuleRebid
+		}
+	}
+	if cfg.LeadRule == "" {
+		cfg.LeadRule = LeadHighestBidder
+	}
+	if cfg.Decks == 0 {
+		cfg.Decks = 1
+	}
+	if cfg.Ties == "" {
+		cfg.Ties = TiesFirstPlayed
+	}
+	return cfg
+}
+
+// NewGame returns an empty game waiting in the lobby.
+// Options cfg leaves empty are filled in with their defaults.
+func NewGame(cfg Config) *Game {
+	cfg = cfg.withDefaults()
+	delay := cfg.ReviewDelayMs
+	if delay == 0 {
+		delay = DefaultReviewDelayMs
+	}
+	return &Game{
+		ID:              cfg.ID,
+		Config:          cfg,
+		Players:         []*Player{},
+		State:           "lobby",
+		CreatorMaxCards: cfg.MaxCards,
+		ReviewDelayMs:   delay,
+		DealSeed:        cfg.Seed,
+	}
+}
+
+// Replay rebuilds a game by applying the actions in log, in order, to a new
+// game created with cfg. Review deadlines are not part of the log and are
+// left unset.
+func Replay(cfg Config, log []LogEntry) (*Game, error) {
+	g := NewGame(cfg)
+	for _, e := range log {
+		a := e.Action
+		a.Time = e.Time
+		if _, err := g.Apply(a); err != nil {
+			return nil, fmt.Errorf("replaying %s action for version %d: %w", a.Type, e.Version, err)
+		}
+	}
+	return g, nil
+}
+
+// Apply validates a and applies it to g. An accepted action bumps the
+// game's version and is appended to its log.
+// The caller must hold g's lock.
+func (g *Game) Apply(a Action) ([]Event, error) {
+	if a.Seed == 0 {
+		a.Seed = rand.Int63()
+	}
+	events, err := g.apply(a, rand.New(rand.NewSource(a.Seed)))
+	if err != nil {
+		return nil, err
+	}
+	g.Version++
+	g.Log = append(g.Log, LogEntry{Version: g.Version, Time: a.Time, Action: a})
+	return events, nil
+}
+
+func (g *Game) apply(a Action, rng *rand.Rand) ([]Event, error) {
+	if a.Type == ActionJoin {
+		return g.join(a.DisplayName, a.Token)
+	}
+	if a.Type == ActionAdvance {
+		return g.advance(rng)
+	}
+	p, _ := FindPlayer(g, a.PlayerID)
+	if p == nil {
+		return nil, errors.New("unknown player")
+	}
+	switch a.Type {
+	case ActionStart:
+		return g.start(rng)
+	case ActionBid:
+		return g.bid(p, a.Bid, a.Blind)
+	case ActionLook:
+		return g.look(p)
+	case ActionPlay:
+		return g.play(p, a.Card)
+	case ActionAck:
+		return g.ack(p, rng)
+	case ActionReset:
+		return g.reset(rng)
+	}
+	return nil, fmt.Errorf("unknown action type %q", a.Type)
+}
+
+// LegalActions returns the start, look, bid, play and ack actions seat may make now.
+// Joining, advancing past a review and resetting are not listed.
+// The caller must hold g's lock.
+func (g *Game) LegalActions(seat string) []Action {
+	p, _ := FindPlayer(g, seat)
+	if p == nil {
+		return nil
+	}
+	var actions []Action
+	switch g.State {
+	case "lobby":
+		if len(g.Players) >= 2 {
+			actions = append(actions, Action{Type: ActionStart, PlayerID: seat})
+		}
+	case "bidding":
+		round := g.CurrentRound
+		blind := g.handHidden(p)
+		if blind {
+			actions = append(actions, Action{Type: ActionLook, PlayerID: seat})
+		}
+		if g.checkBidTurn(p) != nil {
+			return actions
+		}
+		for bid := 0; bid <= round.TotalCards; bid++ {
+			if g.checkBid(bid) == nil {
+				actions = append(actions, Action{Type: ActionBid, PlayerID: seat, Bid: bid, Blind: blind})
+			}
+		}
+	case "playing":
+		if g.nextToPlay() != p {
+			return nil
+		}
+		for _, c := range p.Hand {
+			if g.checkPlay(p, c) == nil {
+				actions = append(actions, Action{Type: ActionPlay, PlayerID: seat, Card: c})
+			}
+		}
+	case "trickReview", "roundReview":
+		if !g.ReviewAcks[seat] {
+			actions = append(actions, Action{Type: ActionAck, PlayerID: seat})
+		}
+	}
+	return actions
+}
+
+// InReview reports whether the table is looking at a finished trick or round
+// and play waits for an ack from every seat or an advance action.
+// The caller must hold g's lock.
+func (g *Game) InReview() bool {
+	return g.State == "trickReview" || g.State == "roundReview"
+}
+
+func (g *Game) join(displayName, token string) ([]Event, error) {
+	if g.State != "lobby" {
+		return nil, errors.New("game already started")
+	}
+	if len(g.Players) >= g.maxPlayers() {
+		return nil, errors.New("game is full")
+	}
+	if g.Config.Sequence == SequenceCustom {
+		if err := checkRounds(g.Config.Rounds, g.Config.dealableCards(), len(g.Players)+1); err != nil {
+			return nil, fmt.Errorf("game is full for its round sequence: %w", err)
+		}
+	}
+	p := &Player{
+		ID:          SeatHandle(len(g.Players)),
+		Token:       token,
+		DisplayName: displayName,
+	}
+	g.Players = append(g.Players, p)
+	// Show the lobby the rounds it would play if the game started now.
+	g.RoundSequence, _ = g.roundSequence()
+	return []Event{{Type: EventPlayerJoined, PlayerID: p.ID}}, nil
+}
+
+func (g *Game) start(rng *rand.Rand) ([]Event, error) {
+	if g.State != "lobby" {
+		return nil, errors.New("game already started")
+	}
+	if len(g.Players) < 2 {
+		return nil, errors.New("need at least 2 players to start")
+	}
+	seq, err := g.roundSequence()
+	if err != nil {
+		return nil, err
+	}
+	g.RoundSequence = seq
+	g.CurrentRoundIndex = 0
+	// Randomly choose a dealer.
+	if err := g.deal(g.roundRNG(0, rng).Intn(len(g.Players)), rng); err != nil {
+		return nil, err
+	}
+	return []Event{{Type: EventRoundStarted, Round: g.CurrentRound.RoundNumber}}, nil
+}
+
+func (g *Game) reset(rng *rand.Rand) ([]Event, error) {
+	if g.State == "lobby" {
+		return nil, errors.New("game has not started")
+	}
+	seq, err := g.roundSequence()
+	if err != nil {
+		return nil, err
+	}
+	g.RoundResults = []RoundResult{}
+	g.CurrentRoundIndex = 0
+	if g.DealSeed != 0 {
+		// The seed of a finished game is no secret, so play again from a new one.
+		g.DealSeed = rng.Int63()
+	}
+	g.endReview()
+	for _, p := range g.Players {
+		p.Score = 0
+		p.MissedBids = 0
+		p.Bags = 0
+	}
+	g.RoundSequence = seq
+	var dealer int
+	if g.CurrentRound != nil {
+		dealer = (g.CurrentRound.DealerIndex + 1) % len(g.Players)
+	} else {
+		dealer = g.roundRNG(0, rng).Intn(len(g.Players))
+	}
+	if err := g.deal(dealer, rng); err != nil {
+		return nil, err
+	}
+	return []Event{{Type: EventGameReset, Round: g.CurrentRound.RoundNumber}}, nil
+}
+
+// maxPlayers is the number of seats at g's table.
+func (g *Game) maxPlayers() int {
+	return min(MaxPlayers, PlayersPerDeck*max(g.Config.Decks, 1))
+}
+
+// maxCards is the number of cards dealt in the largest round.
+func (g *Game) maxCards() int {
+	maxPossible := g.Config.dealableCards() / len(g.Players)
+	if g.CreatorMaxCards <= 0 || g.CreatorMaxCards > maxPossible {
+		return maxPossible
+	}
+	return g.CreatorMaxCards
+}
+
+// deal starts round CurrentRoundIndex with the given dealer and moves the game to bidding.
+func (g *Game) deal(dealer int, rng *rand.Rand) error {
+	round := &Round{
+		RoundNumber: g.CurrentRoundIndex + 1,
+		TotalCards:  g.RoundSequence[g.CurrentRoundIndex],
+		DealerIndex: dealer,
+		Seed:        g.roundSeed(g.CurrentRoundIndex + 1),
+		Bids:        make(map[string]int),
+		BlindBids:   make(map[string]bool),
+		Looked:      make(map[string]bool),
+		BidOrder:    biddingOrder(g.Players, dealer),
+		Tricks:      []Trick{},
+	}
+	for _, p := range g.Players {
+		p.Hand = []Card{}
+		p.CurrentBid = 0
+		p.TricksWon = 0
+	}
+	deck := CreateDeck(g.Config.Decks, g.Config.Jokers)
+	ShuffleDeck(deck, g.roundRNG(round.RoundNumber, rng))
+	leftover, err := DealCards(deck, g.Players, round.TotalCards)
+	if err != nil {
+		return fmt.Errorf("error dealing cards: %w", err)
+	}
+	g.chooseTrump(round, leftover)
+	g.CurrentRound = round
+	g.State = "bidding"
+	return nil
+}
+
+// biddingOrder starts with the player to the left of the dealer and ends with the dealer.
+func biddingOrder(players []*Player, dealer int) []string {
+	n := len(players)
+	order := make([]string, 0, n)
+	for i := 1; i <= n; i++ {
+		order = append(order, players[(dealer+i)%n].ID)
+	}
+	return order
+}
+
+// checkBid reports why the player whose turn it is may not bid bid, if they may not.
+func (g *Game) checkBid(bid int) error {
+	round := g.CurrentRound
+	if bid < 0 || bid > round.TotalCards {
+		return errors.New("invalid bid amount")
+	}
+	if forbidden, ok := g.ForbiddenBid(); ok && bid == forbidden {
+		who := "dealer"
+		if g.Config.BidRule == BidRuleLastBidder {
+			who = "last"
+		}
+		return fmt.Errorf("%s bid cannot make total bids equal total cards (%d)", who, round.TotalCards)
+	}
+	return nil
+}
+
+func (g *Game) bid(p *Player, bid int, blind bool) ([]Event, error) {
+	if g.State != "bidding" {
+		return nil, errors.New("not in bidding phase")
+	}
+	round := g.CurrentRound
+	if err := g.checkBidTurn(p); err != nil {
+		return nil, err
+	}
+	if err := g.checkBid(bid); err != nil {
+		return nil, err
+	}
+	if err := g.checkBlind(p, blind); err != nil {
+		return nil, err
+	}
+	if blind {
+		round.BlindBids[p.ID] = true
+		round.Looked[p.ID] = true
+	}
+	sealed := g.BidsSealed()
+	round.Bids[p.ID] = bid
+	p.CurrentBid = bid
+	p.BidOrder = round.CurrentBidTurn
+	round.CurrentBidTurn++
+	events := []Event{{Type: EventBidPlaced, PlayerID: p.ID, Bid: &bid, Blind: blind}}
+	if sealed {
+		events = []Event{{Type: EventBidSealed, PlayerID: p.ID}}
+	}
+	if round.CurrentBidTurn < len(round.BidOrder) {
+		return events, nil
+	}
+	if sealed {
+		events = append(events, g.revealBids()...)
+		if round.Rebid {
+			return events, nil
+		}
+	}
+	leader := g.openingLeader()
+	g.State = "playing"
+	round.CurrentTrick = &Trick{LeaderID: g.Players[leader].ID, Plays: []Play{}}
+	round.TrickTurnIndex = 0
+	round.TrickLeader = leader
+	return events, nil
+}
+
+// nextToPlay returns the player whose turn it is in the current trick.
+func (g *Game) nextToPlay() *Player {
+	round := g.CurrentRound
+	return g.Players[(round.TrickLeader+round.TrickTurnIndex)%len(g.Players)]
+}
+
+// checkPlay reports why p may not play card to the current trick, if they may not.
+func (g *Game) checkPlay(p *Player, card Card) error {
+	cardIndex := -1
+	for i, c := range p.Hand {
+		if CardEquals(c, card) {
+			cardIndex = i
+			break
+		}
+	}
+	if cardIndex == -1 {
+		return fmt.Errorf("player does not have %s", card)
+	}
+	trick := g.CurrentRound.CurrentTrick
+	rules := g.trickRules()
+	if len(trick.Plays) == 0 {
+		return g.checkTrumpLead(p, card, rules)
+	}
+	// Enforce follow-suit. Whoever holds another card that follows the lead
+	// must play one; a wild joker may always be played but never has to be.
+	leadSuit := rules.SuitOf(trick.Plays[0].Card)
+	if leadSuit == "" || rules.follows(card, leadSuit) {
+		return nil
+	}
+	for i, c := range p.Hand {
+		if i != cardIndex && rules.follows(c, leadSuit) && !(isJoker(c) && rules.Jokers == JokerWild) {
+			return fmt.Errorf("you must follow suit: %s was led", trick.Plays[0].Card)
+		}
+	}
+	return nil
+}
+
+func (g *Game) play(p *Player, card Card) ([]Event, error) {
+	if g.State != "playing" {
+		return nil, errors.New("not in playing phase")
+	}
+	if g.nextToPlay() != p {
+		return nil, errors.New("not your turn to play")
+	}
+	if err := g.checkPlay(p, card); err != nil {
+		return nil, err
+	}
+	var played Card
+	for i, c := range p.Hand {
+		if CardEquals(c, card) {
+			played = c
+			p.Hand = append(append([]Card{}, p.Hand[:i]...), p.Hand[i+1:]...)
+			break
+		}
+	}
+	round := g.CurrentRound
+	round.CurrentTrick.Plays = append(round.CurrentTrick.Plays, Play{PlayerID: p.ID, Card: played})
+	round.TrickTurnIndex++
+	if g.trickRules().inTrump(played) {
+		round.TrumpBroken = true
+	}
+	events := []Event{{Type: EventCardPlayed, PlayerID: p.ID, Card: &played}}
+	if len(round.CurrentTrick.Plays) < len(g.Players) {
+		return events, nil
+	}
+
+	winning := TrickWinner(*round.CurrentTrick, g.trickRules())
+	round.CurrentTrick.WinnerID = winning.PlayerID
+	message := "Trick is over"
+	if winner, _ := FindPlayer(g, winning.PlayerID); winner != nil {
+		winner.TricksWon++
+		message = fmt.Sprintf("%s won the trick!", winner.DisplayName)
+	}
+	g.TrickOverMessage = message
+	round.Tricks = append(round.Tricks, *round.CurrentTrick)
+	completed := *round.CurrentTrick
+	events = append(events, Event{
+		Type:     EventTrickWon,
+		PlayerID: winning.PlayerID,
+		Trick:    &completed,
+		Message:  message,
+	})
+	g.ReviewAcks = make(map[string]bool)
+	if len(p.Hand) > 0 {
+		g.State = "trickReview"
+		return events, nil
+	}
+	// The round is over; score it while the last trick is still on the table.
+	result := g.scoreRound()
+	g.RoundResults = append(g.RoundResults, result)
+	g.State = "roundReview"
+	return append(events, Event{Type: EventRoundScored, Result: &result}), nil
+}
+
+// TrickWinner returns the winning play of a complete trick played under rules.
+// Of two identical cards, the one rules.Ties picks beats the other.
+func TrickWinner(t Trick, rules TrickRules) Play {
+	leadSuit := rules.SuitOf(t.Plays[0].Card)
+	winning := t.Plays[0]
+	for _, p := range t.Plays[1:] {
+		cmp := CompareCards(p.Card, winning.Card, leadSuit, rules)
+		if cmp > 0 || (cmp == 0 && rules.Ties == TiesLastPlayed && CardEquals(p.Card, winning.Card)) {
+			winning = p
+		}
+	}
+	return winning
+}
+
+// ack records that p has seen the trick or round under review,
+// and ends the review once every seat has.
+func (g *Game) ack(p *Player, rng *rand.Rand) ([]Event, error) {
+	if !g.InReview() {
+		return nil, errors.New("nothing to acknowledge")
+	}
+	if g.ReviewAcks[p.ID] {
+		return nil, errors.New("already acknowledged")
+	}
+	g.ReviewAcks[p.ID] = true
+	events := []Event{{Type: EventReviewAcked, PlayerID: p.ID}}
+	if len(g.ReviewAcks) < len(g.Players) {
+		return events, nil
+	}
+	more, err := g.advance(rng)
+	if err != nil {
+		return nil, err
+	}
+	return append(events, more...), nil
+}
+
+// advance ends the current review: after a trick the winner leads the next
+// one, and after a round the next one is dealt or the game finishes.
+func (g *Game) advance(rng *rand.Rand) ([]Event, error) {
+	if !g.InReview() {
+		return nil, errors.New("nothing to review")
+	}
+	round := g.CurrentRound
+	state := g.State
+	g.endReview()
+	if state == "trickReview" {
+		winnerID := round.CurrentTrick.WinnerID
+		_, winner := FindPlayer(g, winnerID)
+		g.State = "playing"
+		round.TrickLeader = winner
+		round.CurrentTrick = &Trick{LeaderID: winnerID, Plays: []Play{}}
+		round.TrickTurnIndex = 0
+		return []Event{{Type: EventTrickStarted, PlayerID: winnerID}}, nil
+	}
+
+	g.CurrentRoundIndex++
+	if g.CurrentRoundIndex < len(g.RoundSequence) {
+		if err := g.deal((round.DealerIndex+1)%len(g.Players), rng); err != nil {
+			return nil, err
+		}
+		return []Event{{Type: EventRoundStarted, Round: g.CurrentRound.RoundNumber}}, nil
+	}
+	g.State = "finished"
+	for _, p := range g.Players {
+		p.MissedBids = 0
+		for _, rr := range g.RoundResults {
+			for _, res := range rr.Results {
+				if res.PlayerID == p.ID && res.TricksWon != res.Bid {
+					p.MissedBids++
+				}
+			}
+		}
+	}
+	return []Event{{Type: EventGameFinished}}, nil
+}
+
+// endReview clears everything kept only while a trick or round is under review.
+func (g *Game) endReview() {
+	g.TrickOverMessage = ""
+	g.ReviewAcks = nil
+	g.ReviewDeadline = time.Time{}
+}
+
+// scoreRound adds each player's score for the current round and returns the results.
+// Each score comes from g.scoringRule, adjusted by blindScore for a blind bid.
+func (g *Game) scoreRound() RoundResult {
+	round := g.CurrentRound
+	result := RoundResult{RoundNumber: round.RoundNumber, TotalCards: round.TotalCards}
+	rule := g.scoringRule()
+	for _, p := range g.Players {
+		bid := round.Bids[p.ID]
+		score := rule.ScoreRound(p, RoundOutcome{
+			Bid:     bid,
+			Tricks:  p.TricksWon,
+			Cards:   round.TotalCards,
+			Round:   round.RoundNumber,
+			Players: len(g.Players),
+		})
+		blind := round.BlindBids[p.ID]
+		if blind {
+			score = blindScore(score, p.TricksWon == bid)
+		}
+		p.Score += score
+		result.Results = append(result.Results, PlayerRoundResult{
+			PlayerID:   p.ID,
+			Bid:        bid,
+			Blind:      blind,
+			TricksWon:  p.TricksWon,
+			RoundScore: score,
+		})
+	}
+	return result
+}
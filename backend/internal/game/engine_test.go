@@ -0,0 +1,30 @@
+package game
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResetNeedsAStartedGame(t *testing.T) {
+	for _, players := range []int{1, 2} {
+		g := newTestGame(t, Config{}, players)
+		_, err := g.Apply(Action{Type: ActionReset, PlayerID: SeatHandle(0)})
+		if err == nil || !strings.Contains(err.Error(), "not started") {
+			t.Errorf("reset in the lobby with %d players: got %v, want an error", players, err)
+		}
+		if g.State != "lobby" || g.Version != players {
+			t.Errorf("rejected reset left the game in %s at version %d", g.State, g.Version)
+		}
+	}
+
+	g := newTestGame(t, Config{}, 2)
+	if _, err := g.Apply(Action{Type: ActionStart, PlayerID: SeatHandle(0)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Apply(Action{Type: ActionReset, PlayerID: SeatHandle(1)}); err != nil {
+		t.Fatalf("reset of a started game: %v", err)
+	}
+	if g.State != "bidding" || g.CurrentRound.RoundNumber != 1 {
+		t.Fatalf("reset left the game in %s at round %d", g.State, g.CurrentRound.RoundNumber)
+	}
+}
@@ -0,0 +1,35 @@
+package game
+
+// Event types pushed to clients following a game.
+const (
+	EventPlayerJoined = "playerJoined"
+	EventRoundStarted = "roundStarted"
+	EventHandSeen     = "handSeen"
+	EventBidPlaced    = "bidPlaced"
+	EventBidSealed    = "bidSealed"
+	EventBidsRevealed = "bidsRevealed"
+	EventRebid        = "rebid"
+	EventCardPlayed   = "cardPlayed"
+	EventTrickWon     = "trickWon"
+	EventTrickStarted = "trickStarted"
+	EventReviewAcked  = "reviewAcked"
+	EventRoundScored  = "roundScored"
+	EventGameFinished = "gameFinished"
+	EventGameReset    = "gameReset"
+)
+
+// Event describes one change to a game.
+// Only the fields relevant to Type are set, and none of them reveal a hidden hand.
+// Seq is the game version the event produced; all events of one action share it.
+type Event struct {
+	Seq      int          `json:"seq"`
+	Type     string       `json:"type"`
+	PlayerID string       `json:"playerId,omitempty"`
+	Bid      *int         `json:"bid,omitempty"`
+	Blind    bool         `json:"blind,omitempty"`
+	Card     *Card        `json:"card,omitempty"`
+	Trick    *Trick       `json:"trick,omitempty"`
+	Round    int          `json:"round,omitempty"`
+	Result   *RoundResult `json:"result,omitempty"`
+	Message  string       `json:"message,omitempty"`
+}
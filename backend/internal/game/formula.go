@@ -0,0 +1,406 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MaxFormulaLength caps the length of a scoring formula.
+const MaxFormulaLength = 500
+
+// FormulaVariables are the names a scoring formula can use, with what they
+// stand for in the round being scored.
+var FormulaVariables = map[string]string{
+	"bid":     "the player's bid",
+	"tricks":  "the tricks the player won",
+	"cards":   "the cards dealt to each player",
+	"round":   "the round number, starting at 1",
+	"players": "the number of players",
+	"made":    "1 if the player won exactly what they bid, 0 otherwise",
+}
+
+// Formula is a parsed scoring formula; it implements ScoringRule.
+//
+// A formula is an integer expression over the FormulaVariables with
+// + - * / %, comparisons (== != < <= > >=) and && || ! giving 1 or 0,
+// cond ? a : b, parentheses and the functions abs(x), min(a, b) and max(a, b).
+// Dividing by zero gives zero. The squared preset, for example, is
+//
+//	made ? 10 + bid * bid : 0
+type Formula struct {
+	src  string
+	root formulaNode
+}
+
+// ParseFormula parses and checks a scoring formula.
+func ParseFormula(src string) (*Formula, error) {
+	if len(src) > MaxFormulaLength {
+		return nil, fmt.Errorf("formula is longer than %d characters", MaxFormulaLength)
+	}
+	toks, err := tokenizeFormula(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &formulaParser{toks: toks}
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEnd {
+		return nil, fmt.Errorf("unexpected %s at position %d", t.describe(), t.pos+1)
+	}
+	return &Formula{src: src, root: root}, nil
+}
+
+// String returns the formula as it was written.
+func (f *Formula) String() string { return f.src }
+
+// ScoreRound evaluates the formula for one player's round.
+func (f *Formula) ScoreRound(_ *Player, o RoundOutcome) int {
+	vars := map[string]int{
+		"bid":     o.Bid,
+		"tricks":  o.Tricks,
+		"cards":   o.Cards,
+		"round":   o.Round,
+		"players": o.Players,
+		"made":    boolInt(o.Tricks == o.Bid),
+	}
+	return f.root.eval(vars)
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// formulaNode is one node of a parsed formula.
+type formulaNode interface {
+	eval(vars map[string]int) int
+}
+
+type numberNode int
+
+func (n numberNode) eval(map[string]int) int { return int(n) }
+
+type variableNode string
+
+func (n variableNode) eval(vars map[string]int) int { return vars[string(n)] }
+
+type unaryNode struct {
+	op string
+	x  formulaNode
+}
+
+func (n unaryNode) eval(vars map[string]int) int {
+	x := n.x.eval(vars)
+	if n.op == "!" {
+		return boolInt(x == 0)
+	}
+	return -x
+}
+
+type binaryNode struct {
+	op   string
+	l, r formulaNode
+}
+
+func (n binaryNode) eval(vars map[string]int) int {
+	l := n.l.eval(vars)
+	// && and || only look at the right side when they need to.
+	switch n.op {
+	case "&&":
+		return boolInt(l != 0 && n.r.eval(vars) != 0)
+	case "||":
+		return boolInt(l != 0 || n.r.eval(vars) != 0)
+	}
+	r := n.r.eval(vars)
+	switch n.op {
+	case "+":
+		return l + r
+	case "-":
+		return l - r
+	case "*":
+		return l * r
+	case "/":
+		if r == 0 {
+			return 0
+		}
+		return l / r
+	case "%":
+		if r == 0 {
+			return 0
+		}
+		return l % r
+	case "==":
+		return boolInt(l == r)
+	case "!=":
+		return boolInt(l != r)
+	case "<":
+		return boolInt(l < r)
+	case "<=":
+		return boolInt(l <= r)
+	case ">":
+		return boolInt(l > r)
+	case ">=":
+		return boolInt(l >= r)
+	}
+	panic("unknown operator " + n.op)
+}
+
+type condNode struct {
+	cond, then, els formulaNode
+}
+
+func (n condNode) eval(vars map[string]int) int {
+	if n.cond.eval(vars) != 0 {
+		return n.then.eval(vars)
+	}
+	return n.els.eval(vars)
+}
+
+type callNode struct {
+	fn   string
+	args []formulaNode
+}
+
+// formulaFuncs are the functions a formula can call, by number of arguments.
+var formulaFuncs = map[string]int{"abs": 1, "min": 2, "max": 2}
+
+func (n callNode) eval(vars map[string]int) int {
+	a := n.args[0].eval(vars)
+	switch n.fn {
+	case "abs":
+		return abs(a)
+	case "min":
+		return min(a, n.args[1].eval(vars))
+	case "max":
+		return max(a, n.args[1].eval(vars))
+	}
+	panic("unknown function " + n.fn)
+}
+
+const (
+	tokEnd = iota
+	tokNumber
+	tokName
+	tokOp
+)
+
+type formulaToken struct {
+	kind int
+	text string
+	pos  int
+}
+
+// describe names t for an error message.
+func (t formulaToken) describe() string {
+	if t.kind == tokEnd {
+		return "end of formula"
+	}
+	return strconv.Quote(t.text)
+}
+
+// formulaOps are the operators and punctuation of the formula language,
+// two-character ones first so they are matched before their prefixes.
+var formulaOps = []string{"==", "!=", "<=", ">=", "&&", "||", "+", "-", "*", "/", "%", "<", ">", "!", "?", ":", "(", ")", ","}
+
+func tokenizeFormula(src string) ([]formulaToken, error) {
+	var toks []formulaToken
+	i := 0
+	for i < len(src) {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c):
+			start := i
+			for i < len(src) && unicode.IsDigit(rune(src[i])) {
+				i++
+			}
+			toks = append(toks, formulaToken{tokNumber, src[start:i], start})
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(src) && (unicode.IsLetter(rune(src[i])) || unicode.IsDigit(rune(src[i])) || src[i] == '_') {
+				i++
+			}
+			toks = append(toks, formulaToken{tokName, src[start:i], start})
+		default:
+			op := ""
+			for _, candidate := range formulaOps {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", src[i], i+1)
+			}
+			toks = append(toks, formulaToken{tokOp, op, i})
+			i += len(op)
+		}
+	}
+	return append(toks, formulaToken{tokEnd, "", len(src)}), nil
+}
+
+// formulaParser is a recursive descent parser over the tokens of a formula.
+// From loosest to tightest: ?:, ||, &&, comparisons, + -, * / %, unary - !.
+type formulaParser struct {
+	toks []formulaToken
+	i    int
+}
+
+func (p *formulaParser) peek() formulaToken { return p.toks[p.i] }
+
+func (p *formulaParser) next() formulaToken {
+	t := p.toks[p.i]
+	if t.kind != tokEnd {
+		p.i++
+	}
+	return t
+}
+
+// accept consumes the next token if it is one of the given operators.
+func (p *formulaParser) accept(ops ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokOp {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			p.i++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *formulaParser) expect(op string) error {
+	if _, ok := p.accept(op); !ok {
+		t := p.peek()
+		return fmt.Errorf("expected %q but found %s at position %d", op, t.describe(), t.pos+1)
+	}
+	return nil
+}
+
+func (p *formulaParser) parseExpr() (formulaNode, error) {
+	cond, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.accept("?"); !ok {
+		return cond, nil
+	}
+	then, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	els, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	return condNode{cond, then, els}, nil
+}
+
+// binaryLevels lists the binary operators from loosest to tightest binding.
+var binaryLevels = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *formulaParser) parseBinary(level int) (formulaNode, error) {
+	if level == len(binaryLevels) {
+		return p.parseUnary()
+	}
+	l, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept(binaryLevels[level]...)
+		if !ok {
+			return l, nil
+		}
+		r, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		l = binaryNode{op, l, r}
+	}
+}
+
+func (p *formulaParser) parseUnary() (formulaNode, error) {
+	if op, ok := p.accept("-", "!"); ok {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op, x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *formulaParser) parsePrimary() (formulaNode, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		n, err := strconv.Atoi(t.text)
+		if err != nil {
+			return nil, fmt.Errorf("number %s at position %d is too large", t.text, t.pos+1)
+		}
+		return numberNode(n), nil
+	case tokName:
+		if _, ok := p.accept("("); ok {
+			return p.parseCall(t)
+		}
+		if _, ok := FormulaVariables[t.text]; !ok {
+			return nil, fmt.Errorf("unknown variable %q at position %d", t.text, t.pos+1)
+		}
+		return variableNode(t.text), nil
+	case tokOp:
+		if t.text == "(" {
+			x, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			return x, p.expect(")")
+		}
+	}
+	return nil, fmt.Errorf("unexpected %s at position %d", t.describe(), t.pos+1)
+}
+
+// parseCall parses the arguments of a call to the function named by t,
+// whose opening parenthesis has been consumed.
+func (p *formulaParser) parseCall(t formulaToken) (formulaNode, error) {
+	arity, ok := formulaFuncs[t.text]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q at position %d", t.text, t.pos+1)
+	}
+	var args []formulaNode
+	for {
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if _, ok := p.accept(","); !ok {
+			break
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if len(args) != arity {
+		return nil, fmt.Errorf("wrong number of arguments to %s at position %d: want %d, got %d", t.text, t.pos+1, arity, len(args))
+	}
+	return callNode{t.text, args}, nil
+}
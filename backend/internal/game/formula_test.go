@@ -0,0 +1,92 @@
+package game
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormulaScoreRound(t *testing.T) {
+	made := RoundOutcome{Bid: 3, Tricks: 3, Cards: 5, Round: 2, Players: 4}
+	missed := RoundOutcome{Bid: 3, Tricks: 1, Cards: 5, Round: 2, Players: 4}
+	for _, tc := range []struct {
+		src  string
+		o    RoundOutcome
+		want int
+	}{
+		// Precedence and associativity.
+		{"1 + 2 * 3", made, 7},
+		{"(1 + 2) * 3", made, 9},
+		{"10 - 4 - 3", made, 3},
+		{"100 / 10 / 5", made, 2},
+		{"7 % 4 * 2", made, 6},
+		{"-2 * 3", made, -6},
+		{"- -2", made, 2},
+		{"!0 + 1", made, 2},
+		{"1 + 2 < 4", made, 1},
+		{"1 < 2 == 1", made, 1},
+		{"0 || 1 && 0", made, 0},
+		{"1 || 0 && 0", made, 1},
+		{"!(1 && 0)", made, 1},
+		// Conditionals, including nested ones.
+		{"made ? 10 + bid * bid : 0", made, 19},
+		{"made ? 10 + bid * bid : 0", missed, 0},
+		{"made ? 1 : tricks > bid ? 2 : 3", missed, 3},
+		{"made ? 1 : tricks < bid ? 2 : 3", missed, 2},
+		{"bid > 2 ? made ? 10 : 5 : 0", missed, 5},
+		{"bid > 2 ? made ? 10 : 5 : 0", made, 10},
+		{"0 ? 1 : 0 ? 2 : 3", made, 3},
+		{"1 + (made ? 1 : 2) * 10", made, 11},
+		// Functions and variables.
+		{"abs(tricks - bid)", missed, 2},
+		{"-abs(tricks - bid) * 10", missed, -20},
+		{"min(bid, tricks)", missed, 1},
+		{"max(bid, tricks) + cards", missed, 8},
+		{"max(min(round, players), 1)", made, 2},
+		{"round * players + cards", made, 13},
+		// Dividing by zero gives zero.
+		{"bid / 0", made, 0},
+		{"bid % 0", made, 0},
+		{"10 / (tricks - bid)", made, 0},
+		{"10 / (tricks - bid)", missed, -5},
+	} {
+		f, err := ParseFormula(tc.src)
+		if err != nil {
+			t.Errorf("ParseFormula(%q): %v", tc.src, err)
+			continue
+		}
+		if got := f.ScoreRound(nil, tc.o); got != tc.want {
+			t.Errorf("%q with bid %d, tricks %d: got %d, want %d", tc.src, tc.o.Bid, tc.o.Tricks, got, tc.want)
+		}
+	}
+}
+
+func TestParseFormulaErrors(t *testing.T) {
+	for _, tc := range []struct {
+		src     string
+		wantErr string
+	}{
+		{"", "unexpected"},
+		{"1 +", "unexpected"},
+		{"(1 + 2", ")"},
+		{"1 2", "unexpected"},
+		{"made ? 1", ":"},
+		{"made ? 1 : ", "unexpected"},
+		{"score + 1", "score"},
+		{"pow(2, 3)", "unknown function"},
+		{"abs()", "unexpected"},
+		{"abs(1, 2)", "wrong number of arguments to abs"},
+		{"min(1)", "wrong number of arguments to min"},
+		{"max(1, 2, 3)", "wrong number of arguments to max"},
+		{"bid $ 2", "$"},
+		{strings.Repeat("1+", MaxFormulaLength/2) + "1", "longer than"},
+	} {
+		_, err := ParseFormula(tc.src)
+		if err == nil {
+			t.Errorf("ParseFormula(%q) succeeded, want an error mentioning %q", tc.src, tc.wantErr)
+			continue
+		}
+		if !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("ParseFormula(%q) = %v, want an error mentioning %q", tc.src, err, tc.wantErr)
+		}
+	}
+}
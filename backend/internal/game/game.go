@@ -1,18 +1,15 @@
 package game
 
 import (
+	"crypto/subtle"
 	"errors"
+	"log"
 	"math/rand"
 	"strings"
 	"sync"
 	"time"
 )
 
-// Initialize random seed.
-func init() {
-	rand.Seed(time.Now().UnixNano())
-}
-
 // Suit and Rank definitions.
 type Suit string
 type Rank int
@@ -53,8 +50,13 @@ type Card struct {
 }
 
 // Player represents a game participant.
+// ID is the public seat handle shown to everyone at the table; Token is the
+// secret session credential handed only to the player who took the seat.
+// Token must never reach a client other than its owner, so games are only
+// ever sent out through GameView.
 type Player struct {
 	ID          string `json:"id"`
+	Token       string `json:"token"`
 	DisplayName string `json:"displayName"`
 	Hand        []Card `json:"hand"`
 	CurrentBid  int    `json:"currentBid"`
@@ -63,6 +65,7 @@ type Player struct {
 	Score       int    `json:"score"`
 	IsBot       bool   `json:"isBot"`
 	MissedBids  int    `json:"missedBids"`
+	Bags        int    `json:"bags"`
 }
 
 // Play represents one card played in a trick.
@@ -80,16 +83,29 @@ type Trick struct {
 
 // Round represents one round of play.
 type Round struct {
-	RoundNumber    int            `json:"roundNumber"`
-	TotalCards     int            `json:"totalCards"`
-	DealerIndex    int            `json:"dealerIndex"`
-	Bids           map[string]int `json:"bids"`
-	BidOrder       []string       `json:"bidOrder"`
-	CurrentBidTurn int            `json:"currentBidTurn"`
-	Tricks         []Trick        `json:"tricks"`
-	CurrentTrick   *Trick         `json:"currentTrick"`
-	TrickTurnIndex int            `json:"trickTurnIndex"`
-	TrickLeader    int            `json:"trickLeader"`
+	RoundNumber int `json:"roundNumber"`
+	TotalCards  int `json:"totalCards"`
+	DealerIndex int `json:"dealerIndex"`
+	// Seed is what a game with a seed shuffled the round's deck with.
+	Seed int64 `json:"seed,string,omitempty"`
+	// Trump is the trump suit for the round, or empty if it is played without trump.
+	Trump string `json:"trump"`
+	// TurnUp is the card turned up to choose Trump under TrumpTurnUp.
+	TurnUp *Card          `json:"turnUp,omitempty"`
+	Bids   map[string]int `json:"bids"`
+	// BlindBids holds the seats that bid without seeing their hand.
+	BlindBids map[string]bool `json:"blindBids,omitempty"`
+	// Looked holds the seats that have seen their hand, under Config.BlindBids.
+	Looked         map[string]bool `json:"looked,omitempty"`
+	BidOrder       []string        `json:"bidOrder"`
+	CurrentBidTurn int             `json:"currentBidTurn"`
+	Tricks         []Trick         `json:"tricks"`
+	CurrentTrick   *Trick          `json:"currentTrick"`
+	TrickTurnIndex int             `json:"trickTurnIndex"`
+	TrickLeader    int             `json:"trickLeader"`
+	TrumpBroken    bool            `json:"trumpBroken"`
+	// Rebid is set when the dealer must bid again under Config.SealedBids.
+	Rebid bool `json:"rebid,omitempty"`
 }
 
 // RoundResult holds results for a round.
@@ -103,62 +119,146 @@ type RoundResult struct {
 type PlayerRoundResult struct {
 	PlayerID   string `json:"playerId"`
 	Bid        int    `json:"bid"`
+	Blind      bool   `json:"blind,omitempty"`
 	TricksWon  int    `json:"tricksWon"`
 	RoundScore int    `json:"roundScore"`
 }
 
 // Game represents the overall game state.
 type Game struct {
-	ID                string         `json:"id"`
-	Players           []*Player      `json:"players"`
-	State             string         `json:"state"` // "lobby", "bidding", "playing", "scoring", "finished"
-	CurrentRound      *Round         `json:"currentRound"`
-	RoundSequence     []int          `json:"roundSequence"`
-	CurrentRoundIndex int            `json:"currentRoundIndex"`
-	CreatorMaxCards   int            `json:"creatorMaxCards"`
-	RoundResults      []RoundResult  `json:"roundResults"`
-	TrickOverMessage  string         `json:"trickOverMessage,omitempty"`
-}
-
-// Global games map and its mutex.
+	// mu guards every field of the game, and of its rounds and players; hold
+	// it through Lock and Unlock for any read or change.
+	mu sync.Mutex
+	ID string `json:"id"`
+	// Version increases by one every time the game changes, so clients can
+	// tell whether the state they hold is still current.
+	Version int `json:"version"`
+	// Config is what the game was created with.
+	Config            Config        `json:"config"`
+	Players           []*Player     `json:"players"`
+	State             string        `json:"state"` // "lobby", "bidding", "playing", "trickReview", "roundReview", "finished"
+	CurrentRound      *Round        `json:"currentRound"`
+	RoundSequence     []int         `json:"roundSequence"`
+	CurrentRoundIndex int           `json:"currentRoundIndex"`
+	CreatorMaxCards   int           `json:"creatorMaxCards"`
+	RoundResults      []RoundResult `json:"roundResults"`
+	// DealSeed is the seed a seeded game deals from: Config.Seed at first,
+	// and a new one after every reset.
+	DealSeed         int64  `json:"dealSeed,string,omitempty"`
+	TrickOverMessage string `json:"trickOverMessage,omitempty"`
+	ReviewDelayMs    int    `json:"reviewDelayMs"`
+	// ReviewAcks holds the seats that have acknowledged the trick or round
+	// under review.
+	ReviewAcks map[string]bool `json:"reviewAcks,omitempty"`
+	// ReviewDeadline, when set, is when the review ends regardless.
+	ReviewDeadline time.Time `json:"reviewDeadline"`
+	// Log is every action accepted since the game was created, which with
+	// Config is enough to rebuild it with Replay.
+	Log []LogEntry `json:"log"`
+}
+
+// Lock acquires the game's lock.
+func (g *Game) Lock() { g.mu.Lock() }
+
+// Unlock releases the game's lock.
+func (g *Game) Unlock() { g.mu.Unlock() }
+
+// Games is the set of games currently being played, loaded from Store on
+// first use. GamesMu only guards the map itself; use GetGame, AddGame,
+// SaveGame and ForgetGame rather than touching it directly, and lock the game
+// before using it.
 var (
 	Games   = make(map[string]*Game)
 	GamesMu sync.Mutex
 )
 
-// CreateDeck returns a standard deck of 54 cards.
-// Four suits have 13 cards each (ranks 2–Ace) and then two jokers are added,
-// which are considered spades with special ranks.
-func CreateDeck() []Card {
+// Store is where games are kept between requests and across restarts.
+// It must be set before any game is looked up.
+var Store GameStore = NewMemoryStore()
+
+// GetGame returns the game with the given ID, loading it from Store if it is
+// not already in play.
+func GetGame(id string) (*Game, bool) {
+	GamesMu.Lock()
+	defer GamesMu.Unlock()
+	if g, ok := Games[id]; ok {
+		return g, true
+	}
+	g, err := Store.Get(id)
+	if err != nil {
+		if !errors.Is(err, ErrGameNotFound) {
+			log.Printf("loading game %s: %v", id, err)
+		}
+		return nil, false
+	}
+	Games[id] = g
+	return g, true
+}
+
+// AddGame registers g under its ID, replacing any game that had it before.
+func AddGame(g *Game) {
+	GamesMu.Lock()
+	defer GamesMu.Unlock()
+	Games[g.ID] = g
+}
+
+// SaveGame writes g to Store, provided nobody else saved it since it was
+// at expectedVersion. The caller must hold g's lock.
+func SaveGame(g *Game, expectedVersion int) error {
+	return Store.Put(g, expectedVersion)
+}
+
+// ForgetGame drops g from the games in play, so the next GetGame reloads it
+// from Store. It does nothing if another copy has taken g's place.
+func ForgetGame(g *Game) {
+	GamesMu.Lock()
+	defer GamesMu.Unlock()
+	if Games[g.ID] == g {
+		delete(Games, g.ID)
+	}
+}
+
+// CreateDeck returns decks full decks put together, for the given joker option.
+// Four suits have 13 cards each (ranks 2–Ace); JokersTrump and JokersSuit
+// add both jokers and JokerWild adds Joker1 only. The jokers are stored as
+// spades with special ranks. Fewer than one deck means one.
+func CreateDeck(decks int, jokers string) []Card {
 	var deck []Card
 	suits := []string{"hearts", "diamonds", "clubs", "spades"}
-	for _, s := range suits {
-		for rank := 2; rank <= 14; rank++ {
-			deck = append(deck, Card{
-				Suit: s,
-				Rank: rank,
-			})
+	for d := 0; d < max(decks, 1); d++ {
+		for _, s := range suits {
+			for rank := 2; rank <= 14; rank++ {
+				deck = append(deck, Card{
+					Suit: s,
+					Rank: rank,
+				})
+			}
+		}
+		switch jokers {
+		case JokersTrump, JokersSuit:
+			// Joker1 is the higher of the two.
+			deck = append(deck, Card{Suit: "spades", Rank: 15})
+			deck = append(deck, Card{Suit: "spades", Rank: 16})
+		case JokerWild:
+			deck = append(deck, Card{Suit: "spades", Rank: 16})
 		}
 	}
-	// Append two jokers.
-	// They are considered spades. Joker1 is the highest card.
-	deck = append(deck, Card{Suit: "spades", Rank: 15})
-	deck = append(deck, Card{Suit: "spades", Rank: 16})
 	return deck
 }
 
-// ShuffleDeck shuffles the deck.
-func ShuffleDeck(deck []Card) {
-	rand.Shuffle(len(deck), func(i, j int) {
+// ShuffleDeck shuffles the deck using rng.
+func ShuffleDeck(deck []Card, rng *rand.Rand) {
+	rng.Shuffle(len(deck), func(i, j int) {
 		deck[i], deck[j] = deck[j], deck[i]
 	})
 }
 
-// DealCards deals cardsPerPlayer cards to each player.
-func DealCards(deck []Card, players []*Player, cardsPerPlayer int) error {
+// DealCards deals cardsPerPlayer cards to each player and returns the cards
+// left over.
+func DealCards(deck []Card, players []*Player, cardsPerPlayer int) ([]Card, error) {
 	totalNeeded := cardsPerPlayer * len(players)
 	if totalNeeded > len(deck) {
-		return errors.New("not enough cards in the deck")
+		return nil, errors.New("not enough cards in the deck")
 	}
 	for i := 0; i < cardsPerPlayer; i++ {
 		for _, p := range players {
@@ -166,13 +266,17 @@ func DealCards(deck []Card, players []*Player, cardsPerPlayer int) error {
 			deck = deck[1:]
 		}
 	}
-	return nil
+	return deck, nil
 }
 
-// IsTrump returns true if the card is trump.
-// In this game, trump cards are those with suit "spades" (which includes the jokers).
-func IsTrump(c Card) bool {
-	return strings.ToLower(c.Suit) == "spades"
+// IsTrump returns true if the card is trump under rules.
+// Under JokersTrump the jokers are always trump, even in a round played
+// without trump.
+func IsTrump(c Card, rules TrickRules) bool {
+	if isJoker(c) {
+		return rules.Jokers == JokersTrump
+	}
+	return rules.Trump != "" && strings.ToLower(c.Suit) == rules.Trump
 }
 
 // CompareTrump compares two trump cards by their rank.
@@ -185,11 +289,20 @@ func CompareTrump(c1, c2 Card) int {
 	return 0
 }
 
-// CompareCards compares two cards given the lead suit.
+// CompareCards compares two cards given the lead suit and the rules of the trick.
 // Returns 1 if c1 wins over c2, -1 if c2 wins over c1, or 0 if they are equal.
-func CompareCards(c1, c2 Card, leadSuit string) int {
-	c1Trump := IsTrump(c1)
-	c2Trump := IsTrump(c2)
+func CompareCards(c1, c2 Card, leadSuit string, rules TrickRules) int {
+	// Jokers that are not trumps beat everything else.
+	if rules.Jokers != JokersTrump && (isJoker(c1) || isJoker(c2)) {
+		if isJoker(c1) && isJoker(c2) {
+			return CompareTrump(c1, c2)
+		} else if isJoker(c1) {
+			return 1
+		}
+		return -1
+	}
+	c1Trump := IsTrump(c1, rules)
+	c2Trump := IsTrump(c2, rules)
 	if c1Trump && c2Trump {
 		return CompareTrump(c1, c2)
 	} else if c1Trump {
@@ -235,6 +348,19 @@ func FindPlayer(g *Game, playerID string) (*Player, int) {
 	return nil, -1
 }
 
+// FindPlayerByToken returns the player holding the given session token.
+func FindPlayerByToken(g *Game, token string) (*Player, int) {
+	if token == "" {
+		return nil, -1
+	}
+	for i, p := range g.Players {
+		if subtle.ConstantTimeCompare([]byte(p.Token), []byte(token)) == 1 {
+			return p, i
+		}
+	}
+	return nil, -1
+}
+
 // CardEquals checks whether two cards are equal.
 func CardEquals(a, b Card) bool {
 	return strings.ToLower(a.Suit) == strings.ToLower(b.Suit) && a.Rank == b.Rank
@@ -0,0 +1,92 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Joker options a game can be created with.
+// JokersTrump adds both jokers as the two highest trumps. JokersSuit adds
+// them as a suit of their own that beats every other card. JokerWild adds a
+// single joker that may be played at any time and wins any trick it is in.
+// JokersNone plays with a 52-card deck.
+const (
+	JokersNone  = "none"
+	JokersTrump = "trump"
+	JokersSuit  = "suit"
+	JokerWild   = "wild"
+)
+
+// JokerSuit is the suit the jokers count as under JokersSuit.
+const JokerSuit = "jokers"
+
+// checkJokers reports what is wrong with the joker option of a config, if anything.
+func checkJokers(jokers string) error {
+	switch jokers {
+	case "", JokersNone, JokersTrump, JokersSuit, JokerWild:
+		return nil
+	}
+	return fmt.Errorf("unknown joker option %q", jokers)
+}
+
+// isJoker reports whether c is a joker.
+// A joker's Suit is only what it is stored with; the rules decide how it plays.
+func isJoker(c Card) bool {
+	return c.Rank > int(Ace)
+}
+
+// TrickRules are the options that decide what may be played to a trick and who wins it.
+// Trump is the trump suit, or empty for no trump, Jokers one of the joker
+// options and Ties one of the tie rules.
+type TrickRules struct {
+	Trump  string
+	Jokers string
+	Ties   string
+}
+
+// trickRules returns the rules for the tricks of the current round.
+func (g *Game) trickRules() TrickRules {
+	return TrickRules{Trump: g.CurrentRound.Trump, Jokers: g.Config.Jokers, Ties: g.Config.Ties}
+}
+
+// Tie rules for identical cards, which only meet when several decks are in
+// play: TiesFirstPlayed lets the card played first win, TiesLastPlayed the
+// card played last.
+const (
+	TiesFirstPlayed = "firstPlayed"
+	TiesLastPlayed  = "lastPlayed"
+)
+
+// checkTies reports what is wrong with the tie rule of a config, if anything.
+func checkTies(rule string) error {
+	switch rule {
+	case "", TiesFirstPlayed, TiesLastPlayed:
+		return nil
+	}
+	return fmt.Errorf("unknown tie rule %q", rule)
+}
+
+// SuitOf returns the suit c counts as, or empty if it counts as none.
+// Jokers that are trumps belong to the trump suit, and to no suit in a round
+// without trump; a wild joker belongs to no suit.
+func (r TrickRules) SuitOf(c Card) string {
+	if !isJoker(c) {
+		return strings.ToLower(c.Suit)
+	}
+	switch r.Jokers {
+	case JokersSuit:
+		return JokerSuit
+	case JokerWild:
+		return ""
+	}
+	return r.Trump
+}
+
+// follows reports whether playing c follows leadSuit.
+// A joker that is a trump does not follow trump led; a wild joker follows anything.
+func (r TrickRules) follows(c Card, leadSuit string) bool {
+	if isJoker(c) {
+		return r.Jokers == JokerWild || (r.Jokers == JokersSuit && leadSuit == JokerSuit)
+	}
+	return strings.ToLower(c.Suit) == leadSuit
+}
@@ -0,0 +1,157 @@
+package game
+
+import "testing"
+
+// mustCards parses cards written as in a game record, such as "10H JK1".
+func mustCards(t *testing.T, s string) []Card {
+	t.Helper()
+	cards, err := parseCards(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cards
+}
+
+// trickOf returns a trick with cards played in turn by seat1, seat2 and so on.
+func trickOf(t *testing.T, s string) Trick {
+	t.Helper()
+	var trick Trick
+	for i, c := range mustCards(t, s) {
+		trick.Plays = append(trick.Plays, Play{PlayerID: SeatHandle(i), Card: c})
+	}
+	return trick
+}
+
+func TestTrickWinnerWithJokers(t *testing.T) {
+	for _, tc := range []struct {
+		jokers, trump string
+		plays         string
+		want          int
+	}{
+		{JokersNone, "spades", "KH AD QH", 0},
+		{JokersNone, "spades", "KH AH 2S", 2},
+		{JokersNone, "", "KH AH 2S", 1},
+		{JokersTrump, "spades", "AS JK2 JK1", 2},
+		{JokersTrump, "spades", "KH JK2 AH", 1},
+		{JokersTrump, "spades", "KH AS JK2", 2},
+		// Under JokersTrump the jokers stay trump in a round without trump.
+		{JokersTrump, "", "KH JK2 AH", 1},
+		{JokersTrump, "", "JK1 AH 2S", 0},
+		{JokersTrump, "", "JK2 AH JK1", 2},
+		{JokersSuit, "spades", "KH AS JK2", 2},
+		{JokersSuit, "spades", "JK2 AS JK1", 2},
+		{JokersSuit, "", "JK1 AH JK2", 0},
+		{JokerWild, "spades", "KH JK1 AS", 1},
+		{JokerWild, "", "JK1 AH KH", 0},
+	} {
+		rules := TrickRules{Trump: tc.trump, Jokers: tc.jokers}
+		got := TrickWinner(trickOf(t, tc.plays), rules)
+		if want := SeatHandle(tc.want); got.PlayerID != want {
+			t.Errorf("%s jokers, trump %q, %s: %s wins, want %s", tc.jokers, tc.trump, tc.plays, got.PlayerID, want)
+		}
+	}
+}
+
+// checkFollow reports whether a seat holding hand may play card to a trick led with lead.
+func checkFollow(t *testing.T, jokers, trump, lead, hand, card string) error {
+	t.Helper()
+	p := &Player{ID: SeatHandle(1), Hand: mustCards(t, hand)}
+	g := &Game{
+		Config:  Config{Jokers: jokers},
+		Players: []*Player{{ID: SeatHandle(0)}, p},
+		CurrentRound: &Round{
+			Trump:        trump,
+			CurrentTrick: &Trick{Plays: []Play{{PlayerID: SeatHandle(0), Card: mustCards(t, lead)[0]}}},
+		},
+	}
+	return g.checkPlay(p, mustCards(t, card)[0])
+}
+
+func TestFollowSuitWithJokers(t *testing.T) {
+	for _, tc := range []struct {
+		jokers, trump    string
+		lead, hand, card string
+		legal            bool
+	}{
+		{JokersNone, "spades", "KH", "2H 3S", "3S", false},
+		{JokersNone, "spades", "KH", "2H 3S", "2H", true},
+		{JokersNone, "spades", "KH", "2D 3S", "3S", true},
+		// A trump joker does not count as the trump suit when following.
+		{JokersTrump, "spades", "AS", "JK1 2H", "2H", true},
+		{JokersTrump, "spades", "AS", "JK1 2H", "JK1", true},
+		{JokersTrump, "spades", "JK1", "2S 3H", "3H", false},
+		{JokersTrump, "spades", "KH", "JK2 2H", "JK2", false},
+		// A trump joker led in a round without trump leads no suit.
+		{JokersTrump, "", "JK1", "2S 3H", "3H", true},
+		{JokersSuit, "spades", "JK2", "JK1 2H", "2H", false},
+		{JokersSuit, "spades", "JK2", "JK1 2H", "JK1", true},
+		{JokersSuit, "spades", "KH", "JK1 2H", "JK1", false},
+		{JokersSuit, "spades", "KH", "JK1 2D", "JK1", true},
+		// A wild joker may always be played and never has to be.
+		{JokerWild, "spades", "KH", "JK1 2H", "JK1", true},
+		{JokerWild, "spades", "KH", "JK1 3S", "3S", true},
+		{JokerWild, "spades", "KH", "JK1 2H 3S", "3S", false},
+		{JokerWild, "spades", "JK1", "2H 3S", "3S", true},
+	} {
+		err := checkFollow(t, tc.jokers, tc.trump, tc.lead, tc.hand, tc.card)
+		if (err == nil) != tc.legal {
+			t.Errorf("%s jokers, trump %q, %s led, holding %s: playing %s gives %v, want legal %t",
+				tc.jokers, tc.trump, tc.lead, tc.hand, tc.card, err, tc.legal)
+		}
+	}
+}
+
+func TestTrickWinnerTies(t *testing.T) {
+	for _, tc := range []struct {
+		jokers, trump string
+		plays         string
+		first, last   int
+	}{
+		{JokersNone, "spades", "KH KH", 0, 1},
+		{JokersNone, "spades", "KH AH KH AH", 1, 3},
+		{JokersNone, "spades", "2S KH 2S", 0, 2},
+		{JokersNone, "", "QD QD QD", 0, 2},
+		// A higher card still beats an identical pair under either rule.
+		{JokersNone, "spades", "KH KH AH", 2, 2},
+		{JokersTrump, "spades", "JK1 AS JK1", 0, 2},
+		{JokersSuit, "", "JK2 JK2", 0, 1},
+		{JokerWild, "spades", "AH JK1 JK1", 1, 2},
+	} {
+		for _, ties := range []string{TiesFirstPlayed, TiesLastPlayed} {
+			want := tc.first
+			if ties == TiesLastPlayed {
+				want = tc.last
+			}
+			rules := TrickRules{Trump: tc.trump, Jokers: tc.jokers, Ties: ties}
+			got := TrickWinner(trickOf(t, tc.plays), rules)
+			if got.PlayerID != SeatHandle(want) {
+				t.Errorf("%s, %s jokers, trump %q, %s: %s wins, want %s", ties, tc.jokers, tc.trump, tc.plays, got.PlayerID, SeatHandle(want))
+			}
+		}
+	}
+}
+
+func TestTwoDecksDealIdenticalCards(t *testing.T) {
+	for _, tc := range []struct {
+		jokers string
+		want   int
+	}{
+		{JokersNone, 104},
+		{JokersTrump, 108},
+		{JokerWild, 106},
+	} {
+		deck := CreateDeck(2, tc.jokers)
+		if len(deck) != tc.want {
+			t.Errorf("two decks with %s jokers have %d cards, want %d", tc.jokers, len(deck), tc.want)
+		}
+		counts := make(map[string]int)
+		for _, c := range deck {
+			counts[c.String()]++
+		}
+		for card, n := range counts {
+			if n != 2 {
+				t.Errorf("two decks with %s jokers hold %s %d times", tc.jokers, card, n)
+			}
+		}
+	}
+}
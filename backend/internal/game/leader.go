@@ -0,0 +1,52 @@
+package game
+
+import "fmt"
+
+// Lead rules a game can be created with, deciding who leads the first trick
+// of a round once bidding is over. Ties between bidders go to whoever bid first.
+const (
+	LeadHighestBidder = "highestBidder"
+	LeadLowestBidder  = "lowestBidder"
+	LeadLeftOfDealer  = "leftOfDealer"
+	LeadDealer        = "dealer"
+)
+
+// checkLeadRule reports what is wrong with the lead rule of a config, if anything.
+func checkLeadRule(rule string) error {
+	switch rule {
+	case "", LeadHighestBidder, LeadLowestBidder, LeadLeftOfDealer, LeadDealer:
+		return nil
+	}
+	return fmt.Errorf("unknown lead rule %q", rule)
+}
+
+// openingLeader returns the index of the player leading the first trick of
+// the current round under the game's lead rule.
+func (g *Game) openingLeader() int {
+	round := g.CurrentRound
+	switch g.Config.LeadRule {
+	case LeadLeftOfDealer:
+		return (round.DealerIndex + 1) % len(g.Players)
+	case LeadDealer:
+		return round.DealerIndex
+	case LeadLowestBidder:
+		return g.bestBidder(func(bid, best int) bool { return bid < best })
+	}
+	return g.bestBidder(func(bid, best int) bool { return bid > best })
+}
+
+// bestBidder returns the index of the player with the best bid, where better
+// tells whether bid beats best; on a tie the earlier bidder wins.
+func (g *Game) bestBidder(better func(bid, best int) bool) int {
+	round := g.CurrentRound
+	leader := -1
+	best := 0
+	for _, id := range round.BidOrder {
+		bid := round.Bids[id]
+		if leader == -1 || better(bid, best) {
+			best = bid
+			_, leader = FindPlayer(g, id)
+		}
+	}
+	return leader
+}
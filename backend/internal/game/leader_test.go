@@ -0,0 +1,38 @@
+package game
+
+import "testing"
+
+func TestOpeningLeader(t *testing.T) {
+	// seat2 deals, so seat3 bids first and seat2 last.
+	order := []string{SeatHandle(2), SeatHandle(3), SeatHandle(0), SeatHandle(1)}
+	for _, tc := range []struct {
+		rule string
+		bids []int
+		want int
+	}{
+		{LeadHighestBidder, []int{1, 3, 0, 2}, 3},
+		{LeadHighestBidder, []int{1, 2, 2, 0}, 3},
+		{LeadHighestBidder, []int{2, 1, 0, 2}, 2},
+		{LeadHighestBidder, []int{0, 0, 0, 0}, 2},
+		{"", []int{1, 2, 2, 0}, 3},
+		{LeadLowestBidder, []int{1, 3, 0, 2}, 0},
+		{LeadLowestBidder, []int{2, 1, 3, 1}, 3},
+		{LeadLowestBidder, []int{0, 1, 0, 0}, 2},
+		{LeadLeftOfDealer, []int{1, 3, 0, 2}, 2},
+		{LeadDealer, []int{1, 3, 0, 2}, 1},
+	} {
+		g := &Game{
+			Config:       Config{LeadRule: tc.rule},
+			CurrentRound: &Round{DealerIndex: 1, BidOrder: order, Bids: make(map[string]int)},
+		}
+		for i := 0; i < 4; i++ {
+			g.Players = append(g.Players, &Player{ID: SeatHandle(i)})
+		}
+		for i, bid := range tc.bids {
+			g.CurrentRound.Bids[order[i]] = bid
+		}
+		if got := g.openingLeader(); got != tc.want {
+			t.Errorf("%q lead rule, bids %v in turn from seat3: seat%d leads, want seat%d", tc.rule, tc.bids, got+1, tc.want+1)
+		}
+	}
+}
@@ -0,0 +1,505 @@
+package game
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// A game record is a plain text account of a game, one fact per line:
+//
+//	# Up and Down the River game record
+//	Game: ABC123
+//	Player seat1: Alice
+//	Player seat2: Bob
+//	MaxCards: 0
+//	ReviewDelayMs: 2000
+//	Trump: fixed spades
+//	BreakTrump: false
+//	BlindBids: false
+//	Jokers: trump
+//	Decks: 1
+//	Scoring: squared
+//	Sequence: upDown
+//	SealedBids: false
+//	BidRule: dealer
+//	LeadRule: highestBidder
+//	Ties: firstPlayed
+//	Seed: 4021786329163405131
+//	RoundSequence: 1 2 1
+//	State: finished
+//
+//	Round 1: cards 1, dealer seat2, seed 8674665223082153551, trump spades
+//	Hand seat1: AS
+//	Hand seat2: 10H
+//	Bids: seat1=1 seat2=0
+//	Trick: seat1 AS, seat2 10H -> seat1
+//	Score: seat1 11 (bid 1, won 1), seat2 10 (bid 0, won 0)
+//
+// A "Reset" line comes before the first round dealt by a reset; in a game with
+// a Seed line it gives the seed of the reset, which draws the game's next
+// seed, as in "Reset: seed 7243561048259190133". Cards are
+// written as Card.String writes them (2S, 10H, QD, AC, JK1, JK2). The seed on
+// each round line is what its deal was shuffled with, so reading a record
+// back replays the game exactly. In a game with a Seed line, every round
+// seed follows from the game seed. The round line ends with the trump suit, or
+// "no trump", and under the turn-up rule "turned up" and the card. Hand lines
+// are checked against the replayed deal, and State tells whether a trick or
+// round review at the end of the record was over. A Formula line follows
+// Scoring when the game scores with its own formula, and Sequence gives the
+// number of rounds after a fixed shape and the rounds after a custom one.
+// A bid followed by "*" was made blind. Bids are listed in the order they
+// were made, so a dealer sent back to bid again appears twice.
+// Score lines are only informative.
+// A trick without "->" is still being played.
+
+const recordTitle = "# Up and Down the River game record"
+
+// WriteRecord writes the record of g to w. The caller must hold g's lock.
+func WriteRecord(w io.Writer, g *Game) error {
+	rec, err := BuildRecord(g)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, recordTitle)
+	fmt.Fprintf(bw, "Game: %s\n", g.ID)
+	for _, p := range g.Players {
+		fmt.Fprintf(bw, "Player %s: %s\n", p.ID, strings.Join(strings.Fields(p.DisplayName), " "))
+	}
+	fmt.Fprintf(bw, "MaxCards: %d\n", g.Config.MaxCards)
+	fmt.Fprintf(bw, "ReviewDelayMs: %d\n", g.Config.ReviewDelayMs)
+	fmt.Fprintf(bw, "Trump: %s %s\n", g.Config.TrumpRule, g.Config.TrumpSuit)
+	fmt.Fprintf(bw, "BreakTrump: %t\n", g.Config.BreakTrump)
+	fmt.Fprintf(bw, "BlindBids: %t\n", g.Config.BlindBids)
+	fmt.Fprintf(bw, "Jokers: %s\n", g.Config.Jokers)
+	fmt.Fprintf(bw, "Decks: %d\n", g.Config.Decks)
+	fmt.Fprintf(bw, "Scoring: %s\n", g.Config.Scoring)
+	if g.Config.Formula != "" {
+		fmt.Fprintf(bw, "Formula: %s\n", strings.Join(strings.Fields(g.Config.Formula), " "))
+	}
+	switch g.Config.Sequence {
+	case SequenceFixed:
+		fmt.Fprintf(bw, "Sequence: %s %d\n", g.Config.Sequence, g.Config.FixedRounds)
+	case SequenceCustom:
+		fmt.Fprintf(bw, "Sequence: %s %s\n", g.Config.Sequence, joinInts(g.Config.Rounds))
+	default:
+		fmt.Fprintf(bw, "Sequence: %s\n", g.Config.Sequence)
+	}
+	fmt.Fprintf(bw, "SealedBids: %t\n", g.Config.SealedBids)
+	fmt.Fprintf(bw, "BidRule: %s\n", g.Config.BidRule)
+	fmt.Fprintf(bw, "LeadRule: %s\n", g.Config.LeadRule)
+	fmt.Fprintf(bw, "Ties: %s\n", g.Config.Ties)
+	if g.Config.Seed != 0 {
+		fmt.Fprintf(bw, "Seed: %d\n", g.Config.Seed)
+	}
+	if len(g.RoundSequence) > 0 {
+		fmt.Fprintf(bw, "RoundSequence: %s\n", joinInts(g.RoundSequence))
+	}
+	fmt.Fprintf(bw, "State: %s\n", g.State)
+	for i, rr := range rec.Rounds {
+		fmt.Fprintln(bw)
+		if rr.ResetSeed != 0 {
+			fmt.Fprintf(bw, "Reset: seed %d\n", rr.ResetSeed)
+		} else if rr.Reset {
+			fmt.Fprintln(bw, "Reset")
+		}
+		fmt.Fprintf(bw, "Round %d: cards %d, dealer %s, seed %d, %s\n", rr.RoundNumber, rr.TotalCards, rr.DealerID, rr.Seed, formatTrump(rr.Trump, rr.TurnUp))
+		for _, p := range g.Players {
+			fmt.Fprintf(bw, "Hand %s: %s\n", p.ID, formatCards(rr.Hands[p.ID]))
+		}
+		if len(rr.Bids) > 0 {
+			bids := make([]string, len(rr.Bids))
+			for j, b := range rr.Bids {
+				bids[j] = fmt.Sprintf("%s=%d", b.PlayerID, b.Bid)
+				if b.Blind {
+					bids[j] += "*"
+				}
+			}
+			fmt.Fprintf(bw, "Bids: %s\n", strings.Join(bids, " "))
+		}
+		for _, t := range rr.Tricks {
+			fmt.Fprintf(bw, "Trick: %s -> %s\n", formatPlays(t.Plays), t.WinnerID)
+		}
+		last := i == len(rec.Rounds)-1
+		if cur := g.CurrentRound.CurrentTrick; last && g.State == "playing" && cur != nil && len(cur.Plays) > 0 {
+			fmt.Fprintf(bw, "Trick: %s\n", formatPlays(cur.Plays))
+		}
+		if rr.Result != nil {
+			scores := make([]string, len(rr.Result.Results))
+			for j, res := range rr.Result.Results {
+				scores[j] = fmt.Sprintf("%s %d (bid %d, won %d)", res.PlayerID, res.RoundScore, res.Bid, res.TricksWon)
+			}
+			fmt.Fprintf(bw, "Score: %s\n", strings.Join(scores, ", "))
+		}
+	}
+	return bw.Flush()
+}
+
+// ReadRecord rebuilds a game from the record in r by replaying it.
+// The game gets the given ID, or the one in the record if id is empty, and
+// every seat gets a fresh session token from newToken.
+func ReadRecord(r io.Reader, id string, newToken func() string) (*Game, error) {
+	rd := &recordReader{id: id, newToken: newToken}
+	sc := bufio.NewScanner(r)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if err := rd.readLine(text); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if err := rd.begin(); err != nil {
+		return nil, err
+	}
+	if rd.g.InReview() && rd.state != "" && rd.state != rd.g.State {
+		if err := rd.apply(Action{Type: ActionAdvance}); err != nil {
+			return nil, err
+		}
+	}
+	if rd.state != "" && rd.state != rd.g.State {
+		return nil, fmt.Errorf("record ends in state %s, not %s", rd.g.State, rd.state)
+	}
+	if rd.sequence != nil && joinInts(rd.sequence) != joinInts(rd.g.RoundSequence) {
+		return nil, fmt.Errorf("round sequence %s does not match %s", joinInts(rd.sequence), joinInts(rd.g.RoundSequence))
+	}
+	return rd.g, nil
+}
+
+// recordReader holds what has been read of a record so far.
+// The game is created from the header at the first line about play.
+type recordReader struct {
+	id        string
+	newToken  func() string
+	cfg       Config
+	players   []string
+	sequence  []int
+	state     string
+	g         *Game
+	reset     bool
+	resetSeed int64
+	dealt     map[string][]Card
+}
+
+func (rd *recordReader) readLine(text string) error {
+	key, value, _ := strings.Cut(text, ":")
+	value = strings.TrimSpace(value)
+	word, arg, _ := strings.Cut(key, " ")
+	if rd.g == nil {
+		switch word {
+		case "Game":
+			if rd.id == "" {
+				rd.id = value
+			}
+			return nil
+		case "Player":
+			if arg != SeatHandle(len(rd.players)) {
+				return fmt.Errorf("expected player %s, got %q", SeatHandle(len(rd.players)), arg)
+			}
+			rd.players = append(rd.players, value)
+			return nil
+		case "MaxCards":
+			n, err := strconv.Atoi(value)
+			rd.cfg.MaxCards = n
+			return err
+		case "ReviewDelayMs":
+			n, err := strconv.Atoi(value)
+			rd.cfg.ReviewDelayMs = n
+			return err
+		case "RoundSequence":
+			seq, err := parseInts(value)
+			rd.sequence = seq
+			return err
+		case "Trump":
+			rule, suit, _ := strings.Cut(value, " ")
+			rd.cfg.TrumpRule, rd.cfg.TrumpSuit = rule, strings.TrimSpace(suit)
+			return checkTrumpRule(rd.cfg.TrumpRule, rd.cfg.TrumpSuit)
+		case "BreakTrump":
+			b, err := strconv.ParseBool(value)
+			rd.cfg.BreakTrump = b
+			return err
+		case "BlindBids":
+			b, err := strconv.ParseBool(value)
+			rd.cfg.BlindBids = b
+			return err
+		case "Jokers":
+			rd.cfg.Jokers = value
+			return checkJokers(value)
+		case "Decks":
+			n, err := strconv.Atoi(value)
+			rd.cfg.Decks = n
+			return err
+		case "Scoring":
+			rd.cfg.Scoring = value
+			return nil
+		case "Formula":
+			rd.cfg.Formula = value
+			return checkScoring(rd.cfg.Scoring, value)
+		case "Sequence":
+			shape, counts, _ := strings.Cut(value, " ")
+			rd.cfg.Sequence = shape
+			ns, err := parseInts(counts)
+			if err != nil {
+				return err
+			}
+			if shape == SequenceFixed && len(ns) == 1 {
+				rd.cfg.FixedRounds = ns[0]
+			} else if shape == SequenceCustom {
+				rd.cfg.Rounds = ns
+			}
+			return checkSequence(rd.cfg)
+		case "SealedBids":
+			b, err := strconv.ParseBool(value)
+			rd.cfg.SealedBids = b
+			return err
+		case "BidRule":
+			rd.cfg.BidRule = value
+			return checkBidRule(value, rd.cfg.SealedBids)
+		case "LeadRule":
+			rd.cfg.LeadRule = value
+			return checkLeadRule(value)
+		case "Ties":
+			rd.cfg.Ties = value
+			return checkTies(value)
+		case "Seed":
+			n, err := strconv.ParseInt(value, 10, 64)
+			rd.cfg.Seed = n
+			return err
+		case "State":
+			rd.state = value
+			return nil
+		}
+		if err := rd.begin(); err != nil {
+			return err
+		}
+	}
+	switch word {
+	case "Reset":
+		rd.reset = true
+		rd.resetSeed = 0
+		if value != "" {
+			if _, err := fmt.Sscanf(value, "seed %d", &rd.resetSeed); err != nil {
+				return fmt.Errorf("invalid reset line: %w", err)
+			}
+		}
+		return nil
+	case "Round":
+		return rd.readRound(arg, value)
+	case "Hand":
+		cards, err := parseCards(value)
+		if err != nil {
+			return err
+		}
+		dealt, ok := rd.dealt[arg]
+		if !ok {
+			return fmt.Errorf("no hand was dealt to %q", arg)
+		}
+		if formatCards(cards) != formatCards(dealt) {
+			return fmt.Errorf("hand of %s is %s, but the seed deals %s", arg, formatCards(cards), formatCards(dealt))
+		}
+		return nil
+	case "Bids":
+		for _, field := range strings.Fields(value) {
+			seat, amount, _ := strings.Cut(field, "=")
+			amount, blind := strings.CutSuffix(amount, "*")
+			bid, err := strconv.Atoi(amount)
+			if err != nil {
+				return fmt.Errorf("invalid bid %q", field)
+			}
+			if p, _ := FindPlayer(rd.g, seat); p != nil && !blind && rd.g.handHidden(p) {
+				// Looking is not recorded; a bid that is not blind implies it.
+				if err := rd.apply(Action{Type: ActionLook, PlayerID: seat}); err != nil {
+					return err
+				}
+			}
+			if err := rd.apply(Action{Type: ActionBid, PlayerID: seat, Bid: bid, Blind: blind}); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "Trick":
+		return rd.readTrick(value)
+	case "Score":
+		return nil
+	}
+	return fmt.Errorf("unknown line %q", text)
+}
+
+// begin creates the game from the header and seats its players.
+func (rd *recordReader) begin() error {
+	if rd.g != nil {
+		return nil
+	}
+	if rd.id == "" {
+		return errors.New("record has no game ID")
+	}
+	rd.cfg.ID = rd.id
+	if err := rd.cfg.Validate(); err != nil {
+		return err
+	}
+	rd.g = NewGame(rd.cfg)
+	for _, name := range rd.players {
+		if err := rd.apply(Action{Type: ActionJoin, DisplayName: name, Token: rd.newToken()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rd *recordReader) apply(a Action) error {
+	_, err := rd.g.Apply(a)
+	return err
+}
+
+// readRound deals the round on a "Round" line with the seed it gives, and
+// checks the trump it names, if any.
+func (rd *recordReader) readRound(number, value string) error {
+	var cards int
+	var dealer string
+	var seed int64
+	parts := strings.SplitN(value, ", ", 4)
+	if _, err := fmt.Sscanf(strings.Join(parts[:min(len(parts), 3)], ", "), "cards %d, dealer %s seed %d", &cards, &dealer, &seed); err != nil {
+		return fmt.Errorf("invalid round line: %w", err)
+	}
+	dealer = strings.TrimSuffix(dealer, ",")
+	g := rd.g
+	var err error
+	switch {
+	case rd.reset:
+		actionSeed := seed
+		if rd.resetSeed != 0 {
+			actionSeed = rd.resetSeed
+		}
+		err = rd.apply(Action{Type: ActionReset, PlayerID: SeatHandle(0), Seed: actionSeed})
+		rd.reset = false
+	case g.State == "lobby":
+		err = rd.apply(Action{Type: ActionStart, PlayerID: SeatHandle(0), Seed: seed})
+	case g.State == "roundReview":
+		err = rd.apply(Action{Type: ActionAdvance, Seed: seed})
+	default:
+		err = fmt.Errorf("round %s starts before the previous one is over", number)
+	}
+	if err != nil {
+		return err
+	}
+	round := g.CurrentRound
+	if round.Seed != 0 && round.Seed != seed {
+		return fmt.Errorf("round %s is dealt from seed %d, but the game seed gives %d", number, seed, round.Seed)
+	}
+	if strconv.Itoa(round.RoundNumber) != number || round.TotalCards != cards || g.Players[round.DealerIndex].ID != dealer {
+		return fmt.Errorf("round %s does not match the replayed round %d with %d cards dealt by %s",
+			number, round.RoundNumber, round.TotalCards, g.Players[round.DealerIndex].ID)
+	}
+	if trump := formatTrump(round.Trump, round.TurnUp); len(parts) == 4 && !strings.EqualFold(parts[3], trump) {
+		return fmt.Errorf("round %s is played with %s, but the seed deals %s", number, parts[3], trump)
+	}
+	rd.dealt = make(map[string][]Card, len(g.Players))
+	for _, p := range g.Players {
+		rd.dealt[p.ID] = append([]Card{}, p.Hand...)
+	}
+	return nil
+}
+
+// readTrick plays the cards on a "Trick" line and checks the winner, if given.
+func (rd *recordReader) readTrick(value string) error {
+	playsText, winner, complete := strings.Cut(value, "->")
+	if rd.g.State == "trickReview" {
+		if err := rd.apply(Action{Type: ActionAdvance}); err != nil {
+			return err
+		}
+	}
+	for _, field := range strings.Split(playsText, ",") {
+		seat, cardText, _ := strings.Cut(strings.TrimSpace(field), " ")
+		card, err := ParseCard(cardText)
+		if err != nil {
+			return err
+		}
+		if err := rd.apply(Action{Type: ActionPlay, PlayerID: seat, Card: card}); err != nil {
+			return fmt.Errorf("%s playing %s: %w", seat, card.String(), err)
+		}
+	}
+	trick := rd.g.CurrentRound.CurrentTrick
+	if !complete {
+		if rd.g.InReview() {
+			return errors.New("trick is complete but has no winner")
+		}
+		return nil
+	}
+	if !rd.g.InReview() {
+		return errors.New("trick has a winner but is not complete")
+	}
+	if winner = strings.TrimSpace(winner); trick.WinnerID != winner {
+		return fmt.Errorf("trick was won by %s, not %s", trick.WinnerID, winner)
+	}
+	return nil
+}
+
+// formatTrump describes the trump of a round for its "Round" line.
+func formatTrump(trump string, turnUp *Card) string {
+	text := "no trump"
+	if trump != "" {
+		text = "trump " + trump
+	}
+	if turnUp != nil {
+		text += ", turned up " + turnUp.String()
+	}
+	return text
+}
+
+func formatCards(cards []Card) string {
+	names := make([]string, len(cards))
+	for i, c := range cards {
+		names[i] = c.String()
+	}
+	return strings.Join(names, " ")
+}
+
+func parseCards(s string) ([]Card, error) {
+	var cards []Card
+	for _, field := range strings.Fields(s) {
+		c, err := ParseCard(field)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, c)
+	}
+	return cards, nil
+}
+
+func formatPlays(plays []Play) string {
+	parts := make([]string, len(plays))
+	for i, p := range plays {
+		parts[i] = p.PlayerID + " " + p.Card.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+func joinInts(ns []int) string {
+	parts := make([]string, len(ns))
+	for i, n := range ns {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, " ")
+}
+
+func parseInts(s string) ([]int, error) {
+	var ns []int
+	for _, field := range strings.Fields(s) {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", field)
+		}
+		ns = append(ns, n)
+	}
+	return ns, nil
+}
@@ -0,0 +1,166 @@
+package game
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTestGame returns a game created with cfg and joined by the given number of players.
+func newTestGame(t *testing.T, cfg Config, players int) *Game {
+	t.Helper()
+	g := NewGame(cfg)
+	for i := 0; i < players; i++ {
+		if _, err := g.Apply(Action{Type: ActionJoin, DisplayName: fmt.Sprintf("Player %d", i+1), Token: fmt.Sprintf("token%d", i)}); err != nil {
+			t.Fatalf("join: %v", err)
+		}
+	}
+	return g
+}
+
+// playRandomly makes up to n random legal moves in g, picking moves and
+// action seeds with rng. It stops early once the game is finished.
+func playRandomly(t *testing.T, g *Game, rng *rand.Rand, n int) {
+	t.Helper()
+	for i := 0; i < n && g.State != "finished"; i++ {
+		var actions []Action
+		for _, p := range g.Players {
+			actions = append(actions, g.LegalActions(p.ID)...)
+		}
+		if len(actions) == 0 {
+			t.Fatalf("no legal action in state %s", g.State)
+		}
+		a := actions[rng.Intn(len(actions))]
+		a.Seed = rng.Int63()
+		if _, err := g.Apply(a); err != nil {
+			t.Fatalf("%s by %s: %v", a.Type, a.PlayerID, err)
+		}
+	}
+}
+
+// tableOf is what an imported game must share with the game it was exported from.
+func tableOf(g *Game) interface{} {
+	type seat struct {
+		Name                             string
+		Hand                             []Card
+		Bid, Tricks, Score, Missed, Bags int
+	}
+	var seats []seat
+	for _, p := range g.Players {
+		seats = append(seats, seat{p.DisplayName, p.Hand, p.CurrentBid, p.TricksWon, p.Score, p.MissedBids, p.Bags})
+	}
+	return struct {
+		State   string
+		Config  Config
+		Round   *Round
+		Index   int
+		Results []RoundResult
+		Seats   []seat
+	}{g.State, g.Config, g.CurrentRound, g.CurrentRoundIndex, g.RoundResults, seats}
+}
+
+func TestRecordRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		cfg     Config
+		players int
+		reset   bool
+	}{
+		{"default", Config{}, 4, false},
+		{"seeded", Config{Seed: 42}, 3, false},
+		{"seeded reset", Config{Seed: 7, MaxCards: 2}, 3, true},
+		{"unseeded reset", Config{MaxCards: 2}, 2, true},
+		{"rotating trump and jokers", Config{TrumpRule: TrumpRotating, Jokers: JokersTrump, MaxCards: 4, Seed: 3}, 4, false},
+		{"turn-up trump", Config{TrumpRule: TrumpTurnUp, Sequence: SequenceDown, MaxCards: 5, Seed: 5}, 5, false},
+		{"two decks", Config{Decks: 2, Ties: TiesLastPlayed, MaxCards: 3, Seed: 11}, 7, false},
+		{"formula scoring", Config{Formula: "made ? 10 + bid * bid : -abs(tricks - bid)", MaxCards: 3}, 3, false},
+		{"blind bids", Config{BlindBids: true, BreakTrump: true, MaxCards: 4, Seed: 13}, 4, false},
+		{"sealed bids", Config{SealedBids: true, MaxCards: 4, Seed: 17}, 4, false},
+		{"custom rounds", Config{Sequence: SequenceCustom, Rounds: []int{3, 1, 2}, LeadRule: LeadLeftOfDealer, Scoring: ScoringBags}, 3, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := tc.cfg
+			cfg.ID = "original"
+			g := newTestGame(t, cfg, tc.players)
+			rng := rand.New(rand.NewSource(1))
+			// Check the game part of the way through as well as where it ends.
+			playRandomly(t, g, rng, 30)
+			checkRoundTrip(t, g)
+			playRandomly(t, g, rng, 10000)
+			if tc.reset {
+				if _, err := g.Apply(Action{Type: ActionReset, PlayerID: SeatHandle(0), Seed: rng.Int63()}); err != nil {
+					t.Fatalf("reset: %v", err)
+				}
+				playRandomly(t, g, rng, 25)
+				checkRoundTrip(t, g)
+				playRandomly(t, g, rng, 10000)
+			}
+			checkRoundTrip(t, g)
+		})
+	}
+}
+
+// checkRoundTrip exports g and imports it again, and fails t unless the
+// imported game is the same as g and exports to the same record.
+func checkRoundTrip(t *testing.T, g *Game) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := WriteRecord(&buf, g); err != nil {
+		t.Fatalf("WriteRecord: %v", err)
+	}
+	token := 0
+	imported, err := ReadRecord(bytes.NewReader(buf.Bytes()), "copy", func() string {
+		token++
+		return fmt.Sprintf("new%d", token)
+	})
+	if err != nil {
+		t.Fatalf("ReadRecord: %v\n%s", err, buf.String())
+	}
+	imported.Config.ID = g.Config.ID
+	if got, want := tableOf(imported), tableOf(g); !reflect.DeepEqual(got, want) {
+		t.Fatalf("imported game differs\ngot  %+v\nwant %+v", got, want)
+	}
+	var again bytes.Buffer
+	if err := WriteRecord(&again, imported); err != nil {
+		t.Fatalf("WriteRecord of the imported game: %v", err)
+	}
+	want := strings.Replace(buf.String(), "Game: original\n", "Game: copy\n", 1)
+	if again.String() != want {
+		t.Fatalf("record changed on import\ngot:\n%s\nwant:\n%s", again.String(), want)
+	}
+}
+
+func TestReadRecordErrors(t *testing.T) {
+	g := newTestGame(t, Config{ID: "original", MaxCards: 2, Seed: 9}, 3)
+	playRandomly(t, g, rand.New(rand.NewSource(1)), 10000)
+	var buf bytes.Buffer
+	if err := WriteRecord(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	record := buf.String()
+	newToken := func() string { return "token" }
+	for _, tc := range []struct {
+		name    string
+		old     string
+		new     string
+		wantErr string
+	}{
+		{"bad card", "Hand seat1: ", "Hand seat1: 1C ", "1C"},
+		{"wrong hand", "Hand seat1: ", "Hand seat1: JK1 ", "hand"},
+		{"unknown header", "Decks:", "Deck:", "Deck"},
+		{"wrong seed", "Seed: 9\n", "Seed: 10\n", "seed"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if !strings.Contains(record, tc.old) {
+				t.Fatalf("record has no %q:\n%s", tc.old, record)
+			}
+			_, err := ReadRecord(strings.NewReader(strings.Replace(record, tc.old, tc.new, 1)), "copy", newToken)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("ReadRecord = %v, want an error mentioning %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package game
+
+import "fmt"
+
+// GameRecord is the full story of a game, rebuilt from its log.
+type GameRecord struct {
+	ID      string        `json:"id"`
+	Config  Config        `json:"config"`
+	Players []PlayerView  `json:"players"`
+	Rounds  []RoundRecord `json:"rounds"`
+	Actions int           `json:"actions"`
+}
+
+// RoundRecord is one round as it was dealt and played.
+// Version is the game version at which the round was dealt; with the versions
+// on bids and tricks it lets a client step to any point with StateAt.
+// Seed is the seed the round was dealt from: the round's own in a game with a
+// seed, and that of the action that dealt it otherwise. Reset is set if that
+// action was a reset, and ResetSeed, in a game with a seed, is the reset's own
+// seed, from which the game's new seed is drawn. Trump and TurnUp are as on Round.
+type RoundRecord struct {
+	Version     int               `json:"version"`
+	Seed        int64             `json:"seed"`
+	Reset       bool              `json:"reset,omitempty"`
+	ResetSeed   int64             `json:"resetSeed,omitempty"`
+	RoundNumber int               `json:"roundNumber"`
+	TotalCards  int               `json:"totalCards"`
+	DealerID    string            `json:"dealerId"`
+	Trump       string            `json:"trump"`
+	TurnUp      *Card             `json:"turnUp,omitempty"`
+	Hands       map[string][]Card `json:"hands"`
+	Bids        []BidRecord       `json:"bids"`
+	Tricks      []TrickRecord     `json:"tricks"`
+	Result      *RoundResult      `json:"result,omitempty"`
+}
+
+// BidRecord is one bid, in the order bids were made.
+type BidRecord struct {
+	Version  int    `json:"version"`
+	PlayerID string `json:"playerId"`
+	Bid      int    `json:"bid"`
+	Blind    bool   `json:"blind,omitempty"`
+}
+
+// TrickRecord is one completed trick and the game version its last card produced.
+type TrickRecord struct {
+	Version int `json:"version"`
+	Trick
+}
+
+// BuildRecord replays the log of g and collects every round, including the
+// hands as they were dealt. The caller must hold g's lock.
+func BuildRecord(g *Game) (*GameRecord, error) {
+	rec := &GameRecord{ID: g.ID, Config: g.Config, Actions: len(g.Log)}
+	r := NewGame(g.Config)
+	var round *RoundRecord
+	for _, e := range g.Log {
+		a := e.Action
+		a.Time = e.Time
+		events, err := r.Apply(a)
+		if err != nil {
+			return nil, fmt.Errorf("replaying %s action for version %d: %w", a.Type, e.Version, err)
+		}
+		for _, ev := range events {
+			switch ev.Type {
+			case EventRoundStarted, EventGameReset:
+				rr := newRoundRecord(r)
+				rr.Reset = ev.Type == EventGameReset
+				if rr.Seed == 0 {
+					rr.Seed = a.Seed
+				} else if rr.Reset {
+					rr.ResetSeed = a.Seed
+				}
+				rec.Rounds = append(rec.Rounds, rr)
+				round = &rec.Rounds[len(rec.Rounds)-1]
+			case EventBidPlaced:
+				round.Bids = append(round.Bids, BidRecord{Version: r.Version, PlayerID: ev.PlayerID, Bid: *ev.Bid, Blind: ev.Blind})
+			case EventBidSealed:
+				// The event keeps the bid, and whether it was blind, hidden; the action has both.
+				round.Bids = append(round.Bids, BidRecord{Version: r.Version, PlayerID: ev.PlayerID, Bid: a.Bid, Blind: a.Blind})
+			case EventTrickWon:
+				round.Tricks = append(round.Tricks, TrickRecord{Version: r.Version, Trick: copyTrick(*ev.Trick)})
+			case EventRoundScored:
+				result := *ev.Result
+				round.Result = &result
+			}
+		}
+	}
+	for _, p := range NewOpenView(r).Players {
+		p.Hand = nil
+		rec.Players = append(rec.Players, p)
+	}
+	return rec, nil
+}
+
+// newRoundRecord starts the record of the round g has just dealt.
+func newRoundRecord(g *Game) RoundRecord {
+	cr := g.CurrentRound
+	rr := RoundRecord{
+		Version:     g.Version,
+		RoundNumber: cr.RoundNumber,
+		TotalCards:  cr.TotalCards,
+		DealerID:    g.Players[cr.DealerIndex].ID,
+		Seed:        cr.Seed,
+		Trump:       cr.Trump,
+		TurnUp:      cr.TurnUp,
+		Hands:       make(map[string][]Card, len(g.Players)),
+	}
+	for _, p := range g.Players {
+		rr.Hands[p.ID] = append([]Card{}, p.Hand...)
+	}
+	return rr
+}
+
+// StateAt rebuilds g as it was at the given version, i.e. after that many
+// actions, with every hand face up. The caller must hold g's lock.
+func StateAt(g *Game, version int) (*GameView, error) {
+	if version < 0 || version > len(g.Log) {
+		return nil, fmt.Errorf("version must be between 0 and %d", len(g.Log))
+	}
+	r, err := Replay(g.Config, g.Log[:version])
+	if err != nil {
+		return nil, err
+	}
+	return NewOpenView(r), nil
+}
@@ -0,0 +1,150 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// RoundOutcome is how one player's round went, for scoring it.
+type RoundOutcome struct {
+	Bid     int
+	Tricks  int
+	Cards   int
+	Round   int
+	Players int
+}
+
+// ScoringRule decides what a player scores for a round.
+type ScoringRule interface {
+	// ScoreRound returns the score of p for the round with outcome o.
+	// It may update counters kept on p across rounds, such as Bags.
+	ScoreRound(p *Player, o RoundOutcome) int
+}
+
+// ScoringFunc adapts an ordinary function to a ScoringRule.
+type ScoringFunc func(p *Player, o RoundOutcome) int
+
+// ScoreRound calls f(p, o).
+func (f ScoringFunc) ScoreRound(p *Player, o RoundOutcome) int {
+	return f(p, o)
+}
+
+// Built-in scoring rules.
+const (
+	ScoringSquared = "squared"
+	ScoringClassic = "classic"
+	ScoringTricks  = "tricks"
+	ScoringPenalty = "penalty"
+	ScoringBags    = "bags"
+	// ScoringFormula scores with the game's own Config.Formula.
+	ScoringFormula = "formula"
+)
+
+// DefaultScoring is the scoring rule used when a game does not choose.
+const DefaultScoring = ScoringSquared
+
+// Under ScoringBags, every BagLimit overtricks a player collects cost them BagPenalty points.
+const (
+	BagLimit   = 10
+	BagPenalty = 100
+)
+
+// ScoringRules holds every scoring rule a game can be created with, by name.
+//
+//   - squared: 10 + bid² for an exact bid, nothing otherwise.
+//   - classic: 10 + bid for an exact bid, nothing otherwise.
+//   - tricks: one point per trick won, plus 10 for an exact bid.
+//   - penalty: 10 + bid for an exact bid, minus the difference on a miss.
+//   - bags: 10 per trick bid and one per overtrick if the bid is made, minus
+//     10 per trick bid if not. A bid of zero scores 50 if no trick is won and
+//     -50 otherwise, with the tricks counting as overtricks.
+var ScoringRules = map[string]ScoringRule{
+	ScoringSquared: ScoringFunc(func(_ *Player, o RoundOutcome) int {
+		if o.Tricks != o.Bid {
+			return 0
+		}
+		return 10 + o.Bid*o.Bid
+	}),
+	ScoringClassic: ScoringFunc(func(_ *Player, o RoundOutcome) int {
+		if o.Tricks != o.Bid {
+			return 0
+		}
+		return 10 + o.Bid
+	}),
+	ScoringTricks: ScoringFunc(func(_ *Player, o RoundOutcome) int {
+		if o.Tricks != o.Bid {
+			return o.Tricks
+		}
+		return o.Tricks + 10
+	}),
+	ScoringPenalty: ScoringFunc(func(_ *Player, o RoundOutcome) int {
+		if o.Tricks != o.Bid {
+			return -abs(o.Bid - o.Tricks)
+		}
+		return 10 + o.Bid
+	}),
+	ScoringBags: ScoringFunc(scoreBags),
+}
+
+func scoreBags(p *Player, o RoundOutcome) int {
+	bid, won := o.Bid, o.Tricks
+	score := 0
+	switch {
+	case bid == 0 && won == 0:
+		score = 50
+	case bid == 0:
+		score = -50
+	case won < bid:
+		return -10 * bid
+	default:
+		score = 10 * bid
+	}
+	overtricks := won - bid
+	score += overtricks
+	p.Bags += overtricks
+	for p.Bags >= BagLimit {
+		p.Bags -= BagLimit
+		score -= BagPenalty
+	}
+	return score
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// checkScoring reports what is wrong with the scoring options of a config, if anything.
+func checkScoring(name, formula string) error {
+	switch {
+	case name == ScoringFormula || (name == "" && formula != ""):
+		if formula == "" {
+			return errors.New("formula scoring needs a formula")
+		}
+		if _, err := ParseFormula(formula); err != nil {
+			return fmt.Errorf("invalid scoring formula: %w", err)
+		}
+		return nil
+	case formula != "":
+		return fmt.Errorf("a scoring formula cannot be used with the %s scoring rule", name)
+	}
+	if _, ok := ScoringRules[name]; name != "" && !ok {
+		return fmt.Errorf("unknown scoring rule %q", name)
+	}
+	return nil
+}
+
+// scoringRule returns the scoring rule g was created with.
+func (g *Game) scoringRule() ScoringRule {
+	if g.Config.Scoring == ScoringFormula {
+		if f, err := ParseFormula(g.Config.Formula); err == nil {
+			return f
+		}
+	}
+	if rule, ok := ScoringRules[g.Config.Scoring]; ok {
+		return rule
+	}
+	return ScoringRules[DefaultScoring]
+}
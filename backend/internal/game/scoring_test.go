@@ -0,0 +1,30 @@
+package game
+
+import "testing"
+
+func TestScoreBags(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		bags        int
+		bid, tricks int
+		want        int
+		wantBags    int
+	}{
+		{"made exactly", 3, 4, 4, 40, 3},
+		{"overtricks below the limit", 3, 2, 5, 23, 6},
+		{"overtricks reaching the limit", 7, 2, 5, 23 - BagPenalty, 0},
+		{"overtricks past the limit", 8, 1, 5, 14 - BagPenalty, 2},
+		{"one short of the limit", 8, 1, 2, 11, 9},
+		{"missed", 9, 4, 3, -40, 9},
+		{"nil made", 9, 0, 0, 50, 9},
+		// The tricks of a failed nil count as overtricks.
+		{"nil failed", 9, 0, 1, -49 - BagPenalty, 0},
+		{"two limits at once", 9, 0, 11, -39 - 2*BagPenalty, 0},
+	} {
+		p := &Player{Bags: tc.bags}
+		got := scoreBags(p, RoundOutcome{Bid: tc.bid, Tricks: tc.tricks})
+		if got != tc.want || p.Bags != tc.wantBags {
+			t.Errorf("%s: scored %d with %d bags, want %d with %d", tc.name, got, p.Bags, tc.want, tc.wantBags)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package game
+
+import "errors"
+
+// With Config.SealedBids, every player bids at once instead of in turn, and
+// nobody sees anyone else's bid until the last one is in. The usual bid rules
+// need someone to bid last, so only BidRuleRebid and BidRuleOff apply.
+
+// BidsSealed reports whether the bids of the current round are still hidden.
+// The caller must hold g's lock.
+func (g *Game) BidsSealed() bool {
+	return g.Config.SealedBids && g.State == "bidding" && !g.CurrentRound.Rebid
+}
+
+// checkBidTurn reports why p may not bid now, if they may not.
+func (g *Game) checkBidTurn(p *Player) error {
+	round := g.CurrentRound
+	if g.BidsSealed() {
+		if _, ok := round.Bids[p.ID]; ok {
+			return errors.New("you have already bid")
+		}
+		return nil
+	}
+	if round.BidOrder[round.CurrentBidTurn] != p.ID {
+		return errors.New("not your turn to bid")
+	}
+	return nil
+}
+
+// revealBids turns the sealed bids over once the last one is in. Under
+// BidRuleRebid, bids that add up to the cards dealt send the dealer back to
+// bid again, last and in the open.
+func (g *Game) revealBids() []Event {
+	round := g.CurrentRound
+	events := []Event{{Type: EventBidsRevealed}}
+	if g.Config.BidRule != BidRuleRebid || round.TotalCards < 2 || round.bidTotal() != round.TotalCards {
+		return events
+	}
+	dealer := g.Players[round.DealerIndex]
+	delete(round.Bids, dealer.ID)
+	delete(round.BlindBids, dealer.ID)
+	dealer.CurrentBid = 0
+	round.CurrentBidTurn = len(round.BidOrder) - 1
+	round.Rebid = true
+	return append(events, Event{Type: EventRebid, PlayerID: dealer.ID})
+}
+
+// bidTotal is the sum of the bids made so far in r.
+func (r *Round) bidTotal() int {
+	total := 0
+	for _, b := range r.Bids {
+		total += b
+	}
+	return total
+}
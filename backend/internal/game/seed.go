@@ -0,0 +1,34 @@
+package game
+
+import "math/rand"
+
+// A game created with Config.Seed deals every round from a seed worked out
+// from the game seed and the round number, and picks its first dealer the
+// same way. Every table created with the same seed and the same number of
+// players is therefore dealt the same cards, which is what duplicate play
+// needs, and any round can be dealt again from the seed recorded on it.
+// A reset draws a new game seed from the reset action's seed, since the old
+// one is revealed once the game is finished. A game without a seed shuffles
+// with the seed of the action that deals, as games always have.
+
+// roundSeed returns the seed round number round of g is dealt from, or zero
+// if g has no seed. Round zero is the choice of the first dealer.
+func (g *Game) roundSeed(round int) int64 {
+	if g.DealSeed == 0 {
+		return 0
+	}
+	// splitmix64, so that neighbouring rounds get unrelated seeds.
+	z := uint64(g.DealSeed) + uint64(round)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return int64(z ^ (z >> 31))
+}
+
+// roundRNG returns the source of randomness for round number round: one
+// seeded from roundSeed if g has a seed, and the action's rng otherwise.
+func (g *Game) roundRNG(round int, rng *rand.Rand) *rand.Rand {
+	if seed := g.roundSeed(round); seed != 0 {
+		return rand.New(rand.NewSource(seed))
+	}
+	return rng
+}
@@ -0,0 +1,53 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSeededGamesDealAlike(t *testing.T) {
+	a := newTestGame(t, Config{Seed: 99}, 4)
+	b := newTestGame(t, Config{Seed: 99}, 4)
+	playRandomly(t, a, rand.New(rand.NewSource(1)), 10000)
+	playRandomly(t, b, rand.New(rand.NewSource(2)), 10000)
+	ra, err := BuildRecord(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rb, err := BuildRecord(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range ra.Rounds {
+		if ra.Rounds[i].Seed != rb.Rounds[i].Seed || ra.Rounds[i].DealerID != rb.Rounds[i].DealerID {
+			t.Fatalf("round %d: seed %d dealt by %s, and seed %d dealt by %s", i+1,
+				ra.Rounds[i].Seed, ra.Rounds[i].DealerID, rb.Rounds[i].Seed, rb.Rounds[i].DealerID)
+		}
+	}
+}
+
+func TestResetDrawsNewSeed(t *testing.T) {
+	g := newTestGame(t, Config{Seed: 99, MaxCards: 2}, 3)
+	playRandomly(t, g, rand.New(rand.NewSource(1)), 10000)
+	first := g.roundSeed(1)
+	if _, err := g.Apply(Action{Type: ActionReset, PlayerID: SeatHandle(0), Seed: 5}); err != nil {
+		t.Fatal(err)
+	}
+	if g.DealSeed == g.Config.Seed {
+		t.Fatalf("reset kept the game seed %d", g.Config.Seed)
+	}
+	if g.CurrentRound.Seed == first {
+		t.Fatalf("round 1 after a reset is dealt from seed %d again", first)
+	}
+	if view := NewGameView(g, SeatHandle(0)); view.Config.Seed != 0 {
+		t.Fatalf("view of a game being played shows seed %d", view.Config.Seed)
+	}
+	// The new seed follows from the reset's own, so the game still replays.
+	r, err := Replay(g.Config, g.Log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.DealSeed != g.DealSeed || r.CurrentRound.Seed != g.CurrentRound.Seed {
+		t.Fatalf("replay deals from %d, not %d", r.DealSeed, g.DealSeed)
+	}
+}
@@ -0,0 +1,128 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Round sequence shapes a game can be created with. The peak is the most
+// cards each player can be dealt, capped by Config.MaxCards.
+// SequenceUpDown climbs from one card to the peak and back down again.
+// SequenceUp only climbs and SequenceDown only comes down. SequenceDownUp
+// comes down from the peak to one card and climbs back. SequenceDoublePeak is
+// like SequenceUpDown but plays the peak round twice. SequenceFixed plays
+// Config.FixedRounds rounds at the peak, and SequenceCustom plays the rounds
+// listed in Config.Rounds.
+const (
+	SequenceUpDown     = "upDown"
+	SequenceUp         = "up"
+	SequenceDown       = "down"
+	SequenceDownUp     = "downUp"
+	SequenceDoublePeak = "doublePeak"
+	SequenceFixed      = "fixed"
+	SequenceCustom     = "custom"
+)
+
+// MaxRounds caps the number of rounds in a game.
+const MaxRounds = 200
+
+// checkSequence reports what is wrong with the round sequence options of a
+// config, if anything. A custom sequence must leave room for two players.
+func checkSequence(cfg Config) error {
+	switch cfg.Sequence {
+	case "", SequenceUpDown, SequenceUp, SequenceDown, SequenceDownUp, SequenceDoublePeak:
+	case SequenceFixed:
+		if cfg.FixedRounds < 1 || cfg.FixedRounds > MaxRounds {
+			return fmt.Errorf("a fixed sequence needs between 1 and %d rounds", MaxRounds)
+		}
+	case SequenceCustom:
+		if len(cfg.Rounds) == 0 || len(cfg.Rounds) > MaxRounds {
+			return fmt.Errorf("a custom sequence needs between 1 and %d rounds", MaxRounds)
+		}
+		return checkRounds(cfg.Rounds, cfg.withDefaults().dealableCards(), 2)
+	default:
+		return fmt.Errorf("unknown round sequence %q", cfg.Sequence)
+	}
+	if cfg.Sequence != SequenceFixed && cfg.FixedRounds != 0 {
+		return errors.New("fixedRounds only applies to the fixed sequence")
+	}
+	if cfg.Sequence != SequenceCustom && len(cfg.Rounds) > 0 {
+		return errors.New("rounds only applies to the custom sequence")
+	}
+	return nil
+}
+
+// checkRounds reports whether every round in rounds can be dealt from the
+// deck dealable cards to the given number of players.
+func checkRounds(rounds []int, deck, players int) error {
+	for i, cards := range rounds {
+		if cards < 1 {
+			return fmt.Errorf("round %d must deal at least one card", i+1)
+		}
+		if cards*players > deck {
+			return fmt.Errorf("round %d deals %d cards to each of %d players, but only %d cards can be dealt", i+1, cards, players, deck)
+		}
+	}
+	return nil
+}
+
+// dealableCards is how many cards of the deck can be dealt to the players.
+func (cfg Config) dealableCards() int {
+	deck := len(CreateDeck(cfg.Decks, cfg.Jokers))
+	if cfg.TrumpRule == TrumpTurnUp {
+		// One card is kept back to turn up.
+		deck--
+	}
+	return deck
+}
+
+// roundSequence returns the number of cards dealt in each round for the
+// players seated now.
+func (g *Game) roundSequence() ([]int, error) {
+	if len(g.Players) == 0 {
+		return nil, errors.New("no players to deal to")
+	}
+	peak := g.maxCards()
+	var seq []int
+	switch g.Config.Sequence {
+	case SequenceUp:
+		seq = countRounds(1, peak)
+	case SequenceDown:
+		seq = countRounds(peak, 1)
+	case SequenceDownUp:
+		seq = countRounds(peak, 1)
+		if peak >= 2 {
+			seq = append(seq, countRounds(2, peak)...)
+		}
+	case SequenceDoublePeak:
+		seq = append(countRounds(1, peak), countRounds(peak, 1)...)
+	case SequenceFixed:
+		for i := 0; i < g.Config.FixedRounds; i++ {
+			seq = append(seq, peak)
+		}
+	case SequenceCustom:
+		if err := checkRounds(g.Config.Rounds, g.Config.dealableCards(), len(g.Players)); err != nil {
+			return nil, err
+		}
+		seq = append(seq, g.Config.Rounds...)
+	default:
+		seq = ComputeRoundSequence(peak)
+	}
+	return seq, nil
+}
+
+// countRounds returns the card counts from one round to another, counting
+// up or down.
+func countRounds(from, to int) []int {
+	var seq []int
+	if from <= to {
+		for n := from; n <= to; n++ {
+			seq = append(seq, n)
+		}
+	} else {
+		for n := from; n >= to; n-- {
+			seq = append(seq, n)
+		}
+	}
+	return seq
+}
@@ -0,0 +1,62 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRoundSequence(t *testing.T) {
+	// Four players share the 54-card deck, so at most 13 cards each.
+	const deckPeak = 13
+	for _, tc := range []struct {
+		sequence string
+		peak     int
+		want     []int
+	}{
+		{SequenceUpDown, 1, []int{1}},
+		{SequenceUpDown, 2, []int{1, 2, 1}},
+		{SequenceUpDown, deckPeak, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1}},
+		{SequenceUp, 1, []int{1}},
+		{SequenceUp, 2, []int{1, 2}},
+		{SequenceUp, deckPeak, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13}},
+		{SequenceDown, 1, []int{1}},
+		{SequenceDown, 2, []int{2, 1}},
+		{SequenceDown, deckPeak, []int{13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1}},
+		{SequenceDownUp, 1, []int{1}},
+		{SequenceDownUp, 2, []int{2, 1, 2}},
+		{SequenceDownUp, deckPeak, []int{13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13}},
+		{SequenceDoublePeak, 1, []int{1, 1}},
+		{SequenceDoublePeak, 2, []int{1, 2, 2, 1}},
+		{SequenceDoublePeak, deckPeak, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1}},
+		{SequenceFixed, 1, []int{1, 1, 1}},
+		{SequenceFixed, 2, []int{2, 2, 2}},
+		{SequenceFixed, deckPeak, []int{13, 13, 13}},
+	} {
+		cfg := Config{Sequence: tc.sequence}
+		if tc.peak != deckPeak {
+			cfg.MaxCards = tc.peak
+		}
+		if tc.sequence == SequenceFixed {
+			cfg.FixedRounds = 3
+		}
+		g := newTestGame(t, cfg, 4)
+		got, err := g.roundSequence()
+		if err != nil {
+			t.Errorf("%s at peak %d: %v", tc.sequence, tc.peak, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s at peak %d: got %v, want %v", tc.sequence, tc.peak, got, tc.want)
+		}
+	}
+}
+
+func TestCustomRoundSequence(t *testing.T) {
+	g := newTestGame(t, Config{Sequence: SequenceCustom, Rounds: []int{3, 1, 13}}, 4)
+	if got, err := g.roundSequence(); err != nil || !reflect.DeepEqual(got, []int{3, 1, 13}) {
+		t.Fatalf("got %v, %v", got, err)
+	}
+	if _, err := g.Apply(Action{Type: ActionJoin, DisplayName: "Player 5", Token: "token4"}); err == nil {
+		t.Fatal("a fifth player joined a game dealing 13 cards each")
+	}
+}
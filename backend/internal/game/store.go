@@ -0,0 +1,211 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrGameNotFound is returned by a GameStore that holds no game with the requested ID.
+	ErrGameNotFound = errors.New("game not found")
+	// ErrVersionConflict is returned by Put when the stored game is not at the expected version,
+	// because someone else saved it in the meantime.
+	ErrVersionConflict = errors.New("game was changed by someone else")
+)
+
+// GameStore keeps games between requests and across restarts.
+// Put only succeeds if the stored copy is still at expectedVersion, with a
+// missing game counting as version 0, so concurrent writers cannot silently
+// overwrite each other's changes.
+type GameStore interface {
+	Get(id string) (*Game, error)
+	Put(g *Game, expectedVersion int) error
+	List() ([]string, error)
+	Delete(id string) error
+}
+
+// MemoryStore is a GameStore that keeps games in memory only.
+// Games are lost when the process exits.
+type MemoryStore struct {
+	mu       sync.Mutex
+	games    map[string]*Game
+	versions map[string]int
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		games:    make(map[string]*Game),
+		versions: make(map[string]int),
+	}
+}
+
+// Get returns the stored game itself, not a copy.
+func (s *MemoryStore) Get(id string) (*Game, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.games[id]
+	if !ok {
+		return nil, ErrGameNotFound
+	}
+	return g, nil
+}
+
+// Put stores g. The caller must hold g's lock.
+func (s *MemoryStore) Put(g *Game, expectedVersion int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.versions[g.ID] != expectedVersion {
+		return ErrVersionConflict
+	}
+	s.games[g.ID] = g
+	s.versions[g.ID] = g.Version
+	return nil
+}
+
+// List returns the IDs of all stored games in sorted order.
+func (s *MemoryStore) List() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]string, 0, len(s.games))
+	for id := range s.games {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Delete removes the game with the given ID.
+func (s *MemoryStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.games[id]; !ok {
+		return ErrGameNotFound
+	}
+	delete(s.games, id)
+	delete(s.versions, id)
+	return nil
+}
+
+// FileStore is a GameStore that keeps each game as a JSON file in a directory.
+// Files are replaced atomically, so a crash never leaves a half-written game.
+type FileStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewFileStore returns a FileStore keeping games in dir, creating it if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating game directory: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return "", fmt.Errorf("invalid game ID %q", id)
+	}
+	return filepath.Join(s.dir, id+".json"), nil
+}
+
+// Get loads a fresh copy of the game from disk.
+func (s *FileStore) Get(id string) (*Game, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load(id)
+}
+
+func (s *FileStore) load(id string) (*Game, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrGameNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	g := &Game{}
+	if err := json.Unmarshal(data, g); err != nil {
+		return nil, fmt.Errorf("reading game %s: %w", id, err)
+	}
+	g.Config = g.Config.withDefaults()
+	return g, nil
+}
+
+// Put writes g to disk. The caller must hold g's lock.
+func (s *FileStore) Put(g *Game, expectedVersion int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path, err := s.path(g.ID)
+	if err != nil {
+		return err
+	}
+	stored := 0
+	if old, err := s.load(g.ID); err == nil {
+		stored = old.Version
+	} else if !errors.Is(err, ErrGameNotFound) {
+		return err
+	}
+	if stored != expectedVersion {
+		return ErrVersionConflict
+	}
+	data, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.dir, g.ID+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// List returns the IDs of all stored games in sorted order.
+func (s *FileStore) List() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, e := range entries {
+		if id, ok := strings.CutSuffix(e.Name(), ".json"); ok && !e.IsDir() {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// Delete removes the game with the given ID.
+func (s *FileStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); errors.Is(err, os.ErrNotExist) {
+		return ErrGameNotFound
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Trump rules a game can be created with.
+// TrumpFixed makes Config.TrumpSuit trump in every round. TrumpRotating starts
+// with Config.TrumpSuit and moves on to the next suit in TrumpRotation each
+// round. TrumpTurnUp turns up the first card left over after the deal and
+// makes its suit trump. TrumpNone plays every round without trump.
+const (
+	TrumpFixed    = "fixed"
+	TrumpRotating = "rotating"
+	TrumpTurnUp   = "turnUp"
+	TrumpNone     = "none"
+)
+
+// DefaultTrumpSuit is trump when a game does not choose.
+const DefaultTrumpSuit = "spades"
+
+// TrumpRotation is the order in which suits take turns being trump under TrumpRotating.
+var TrumpRotation = []string{"spades", "hearts", "diamonds", "clubs"}
+
+// checkTrumpRule reports what is wrong with the trump options of a config, if anything.
+func checkTrumpRule(rule, suit string) error {
+	switch rule {
+	case "", TrumpFixed, TrumpRotating, TrumpTurnUp, TrumpNone:
+	default:
+		return fmt.Errorf("unknown trump rule %q", rule)
+	}
+	if suit != "" && rotationIndex(suit) < 0 {
+		return fmt.Errorf("unknown trump suit %q", suit)
+	}
+	return nil
+}
+
+func rotationIndex(suit string) int {
+	for i, s := range TrumpRotation {
+		if s == strings.ToLower(suit) {
+			return i
+		}
+	}
+	return -1
+}
+
+// chooseTrump sets the trump of a round just dealt, given the cards left
+// over after the deal.
+func (g *Game) chooseTrump(round *Round, leftover []Card) {
+	round.Trump = ""
+	round.TurnUp = nil
+	switch g.Config.TrumpRule {
+	case TrumpFixed:
+		round.Trump = g.Config.TrumpSuit
+	case TrumpRotating:
+		start := rotationIndex(g.Config.TrumpSuit)
+		round.Trump = TrumpRotation[(start+round.RoundNumber-1)%len(TrumpRotation)]
+	case TrumpTurnUp:
+		// A joker turned up leaves the round without trump.
+		if len(leftover) > 0 {
+			turnUp := leftover[0]
+			round.TurnUp = &turnUp
+			if !isJoker(turnUp) {
+				round.Trump = strings.ToLower(turnUp.Suit)
+			}
+		}
+	}
+}
+
+// inTrump reports whether c belongs to the trump suit, jokers that are trumps included.
+func (r TrickRules) inTrump(c Card) bool {
+	return r.Trump != "" && r.SuitOf(c) == r.Trump
+}
+
+// checkTrumpLead reports why p may not lead card, if they may not. When the
+// game plays with BreakTrump, trump may only be led once a trump has been
+// played in the round, or by a player who holds nothing else.
+func (g *Game) checkTrumpLead(p *Player, card Card, rules TrickRules) error {
+	if !g.Config.BreakTrump || g.CurrentRound.TrumpBroken || !rules.inTrump(card) {
+		return nil
+	}
+	for _, c := range p.Hand {
+		if !rules.inTrump(c) {
+			return fmt.Errorf("trump has not been broken: you cannot lead %s while you hold other suits", card)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package game
+
+import "testing"
+
+func TestLeadTrump(t *testing.T) {
+	for _, tc := range []struct {
+		breakTrump, broken bool
+		jokers, trump      string
+		hand, card         string
+		legal              bool
+	}{
+		{true, false, JokersNone, "spades", "2S 3H", "2S", false},
+		{true, false, JokersNone, "spades", "2S 3H", "3H", true},
+		{true, true, JokersNone, "spades", "2S 3H", "2S", true},
+		{false, false, JokersNone, "spades", "2S 3H", "2S", true},
+		// Holding only trump, trump may be led before it is broken.
+		{true, false, JokersNone, "spades", "2S AS", "AS", true},
+		{true, false, JokersNone, "", "2S 3H", "2S", true},
+		// Trump jokers count as trump, both to lead and to hold.
+		{true, false, JokersTrump, "spades", "JK1 3H", "JK1", false},
+		{true, false, JokersTrump, "spades", "JK1 2S", "JK1", true},
+		{true, false, JokersTrump, "spades", "JK1 2S", "2S", true},
+		{true, false, JokersTrump, "", "JK1 3H", "JK1", true},
+		{true, false, JokersSuit, "spades", "JK1 2S", "JK1", true},
+		{true, false, JokersSuit, "spades", "JK1 2S", "2S", false},
+		{true, false, JokerWild, "spades", "JK1 2S", "2S", false},
+		{true, false, JokerWild, "spades", "JK1 2S", "JK1", true},
+	} {
+		p := &Player{ID: SeatHandle(0), Hand: mustCards(t, tc.hand)}
+		g := &Game{
+			Config:       Config{BreakTrump: tc.breakTrump, Jokers: tc.jokers},
+			Players:      []*Player{p},
+			CurrentRound: &Round{Trump: tc.trump, TrumpBroken: tc.broken, CurrentTrick: &Trick{}},
+		}
+		err := g.checkPlay(p, mustCards(t, tc.card)[0])
+		if (err == nil) != tc.legal {
+			t.Errorf("break trump %t, broken %t, %s jokers, trump %q, holding %s: leading %s gives %v, want legal %t",
+				tc.breakTrump, tc.broken, tc.jokers, tc.trump, tc.hand, tc.card, err, tc.legal)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package game
+
+import (
+	"fmt"
+	"time"
+)
+
+// PlayerView is a player as seen from one seat at the table.
+// Only the viewer's own hand is included, and only once they have looked at
+// it; everyone else is reduced to a card count.
+type PlayerView struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	Hand        []Card `json:"hand,omitempty"`
+	CardCount   int    `json:"cardCount"`
+	CurrentBid  int    `json:"currentBid"`
+	BidOrder    int    `json:"bidOrder"`
+	TricksWon   int    `json:"tricksWon"`
+	Score       int    `json:"score"`
+	IsBot       bool   `json:"isBot"`
+	MissedBids  int    `json:"missedBids"`
+	Bags        int    `json:"bags"`
+}
+
+// RoundView is a copy of a Round safe to hand to any seat.
+// ForbiddenBid, when set, is the bid the player whose turn it is may not make.
+// While bids are sealed, Bids only holds the viewer's own and Sealed marks
+// every seat that has bid.
+type RoundView struct {
+	RoundNumber    int             `json:"roundNumber"`
+	TotalCards     int             `json:"totalCards"`
+	DealerIndex    int             `json:"dealerIndex"`
+	Trump          string          `json:"trump"`
+	TurnUp         *Card           `json:"turnUp,omitempty"`
+	Bids           map[string]int  `json:"bids"`
+	BlindBids      map[string]bool `json:"blindBids,omitempty"`
+	BidOrder       []string        `json:"bidOrder"`
+	CurrentBidTurn int             `json:"currentBidTurn"`
+	Tricks         []Trick         `json:"tricks"`
+	CurrentTrick   *Trick          `json:"currentTrick"`
+	TrickTurnIndex int             `json:"trickTurnIndex"`
+	TrickLeader    int             `json:"trickLeader"`
+	TrumpBroken    bool            `json:"trumpBroken"`
+	Rebid          bool            `json:"rebid,omitempty"`
+	Sealed         map[string]bool `json:"sealed,omitempty"`
+	ForbiddenBid   *int            `json:"forbiddenBid,omitempty"`
+}
+
+// GameView is the redacted game state sent to a single seat.
+// Seat is the viewer's own public handle, or empty for spectators.
+// Config holds the options the game was created with, which are public but
+// for the seed, which would reveal every hand until the game is finished.
+type GameView struct {
+	ID                string        `json:"id"`
+	Version           int           `json:"version"`
+	Config            Config        `json:"config"`
+	Seat              string        `json:"seat"`
+	Players           []PlayerView  `json:"players"`
+	State             string        `json:"state"`
+	CurrentRound      *RoundView    `json:"currentRound"`
+	RoundSequence     []int         `json:"roundSequence"`
+	CurrentRoundIndex int           `json:"currentRoundIndex"`
+	CreatorMaxCards   int           `json:"creatorMaxCards"`
+	RoundResults      []RoundResult `json:"roundResults"`
+	TrickOverMessage  string        `json:"trickOverMessage,omitempty"`
+	ReviewAcks        []string      `json:"reviewAcks,omitempty"`
+	ReviewDeadline    *time.Time    `json:"reviewDeadline,omitempty"`
+}
+
+// SeatHandle returns the public handle for the player sitting at index i.
+func SeatHandle(i int) string {
+	return fmt.Sprintf("seat%d", i+1)
+}
+
+// NewGameView builds the state visible to the player with the public ID viewerID.
+// An unknown or empty viewerID yields a spectator view with no hands.
+func NewGameView(g *Game, viewerID string) *GameView {
+	return newView(g, viewerID, false)
+}
+
+// NewOpenView builds the state with every hand face up, for reviewing games
+// that are over. It must never be sent for a game still being played.
+func NewOpenView(g *Game) *GameView {
+	return newView(g, "", true)
+}
+
+func newView(g *Game, viewerID string, allHands bool) *GameView {
+	v := &GameView{
+		ID:                g.ID,
+		Version:           g.Version,
+		Config:            g.Config,
+		State:             g.State,
+		RoundSequence:     g.RoundSequence,
+		CurrentRoundIndex: g.CurrentRoundIndex,
+		CreatorMaxCards:   g.CreatorMaxCards,
+		TrickOverMessage:  g.TrickOverMessage,
+	}
+	// The seed reveals every deal, and after a reset it no longer gives this game's deals.
+	if g.State != "finished" || g.DealSeed != g.Config.Seed {
+		v.Config.Seed = 0
+	}
+	for _, p := range g.Players {
+		pv := PlayerView{
+			ID:          p.ID,
+			DisplayName: p.DisplayName,
+			CardCount:   len(p.Hand),
+			CurrentBid:  p.CurrentBid,
+			BidOrder:    p.BidOrder,
+			TricksWon:   p.TricksWon,
+			Score:       p.Score,
+			IsBot:       p.IsBot,
+			MissedBids:  p.MissedBids,
+			Bags:        p.Bags,
+		}
+		if g.BidsSealed() && p.ID != viewerID {
+			// The order the bids came in would tell who bid first.
+			pv.CurrentBid = 0
+			pv.BidOrder = 0
+		}
+		if viewerID != "" && p.ID == viewerID {
+			v.Seat = p.ID
+			if !g.handHidden(p) {
+				pv.Hand = append([]Card{}, p.Hand...)
+			}
+		} else if allHands {
+			pv.Hand = append([]Card{}, p.Hand...)
+		}
+		v.Players = append(v.Players, pv)
+	}
+	if g.CurrentRound != nil {
+		v.CurrentRound = newRoundView(g.CurrentRound)
+		if forbidden, ok := g.ForbiddenBid(); ok {
+			v.CurrentRound.ForbiddenBid = &forbidden
+		}
+		if g.BidsSealed() {
+			sealBids(v.CurrentRound, viewerID)
+		}
+	}
+	for _, p := range g.Players {
+		if g.ReviewAcks[p.ID] {
+			v.ReviewAcks = append(v.ReviewAcks, p.ID)
+		}
+	}
+	if !g.ReviewDeadline.IsZero() {
+		deadline := g.ReviewDeadline
+		v.ReviewDeadline = &deadline
+	}
+	for _, rr := range g.RoundResults {
+		rr.Results = append([]PlayerRoundResult{}, rr.Results...)
+		v.RoundResults = append(v.RoundResults, rr)
+	}
+	return v
+}
+
+func newRoundView(r *Round) *RoundView {
+	rv := &RoundView{
+		RoundNumber:    r.RoundNumber,
+		TotalCards:     r.TotalCards,
+		DealerIndex:    r.DealerIndex,
+		Trump:          r.Trump,
+		Bids:           make(map[string]int, len(r.Bids)),
+		CurrentBidTurn: r.CurrentBidTurn,
+		TrickTurnIndex: r.TrickTurnIndex,
+		TrickLeader:    r.TrickLeader,
+		TrumpBroken:    r.TrumpBroken,
+		Rebid:          r.Rebid,
+	}
+	for id, bid := range r.Bids {
+		rv.Bids[id] = bid
+	}
+	for id, blind := range r.BlindBids {
+		if blind {
+			if rv.BlindBids == nil {
+				rv.BlindBids = make(map[string]bool)
+			}
+			rv.BlindBids[id] = true
+		}
+	}
+	if r.TurnUp != nil {
+		turnUp := *r.TurnUp
+		rv.TurnUp = &turnUp
+	}
+	rv.BidOrder = append(rv.BidOrder, r.BidOrder...)
+	for _, t := range r.Tricks {
+		rv.Tricks = append(rv.Tricks, copyTrick(t))
+	}
+	if r.CurrentTrick != nil {
+		t := copyTrick(*r.CurrentTrick)
+		rv.CurrentTrick = &t
+	}
+	return rv
+}
+
+// copyTrick returns a copy of t that shares no slices with the original.
+func copyTrick(t Trick) Trick {
+	t.Plays = append([]Play{}, t.Plays...)
+	return t
+}
+
+// sealBids hides every bid in rv but the viewer's own, along with whether it was blind.
+func sealBids(rv *RoundView, viewerID string) {
+	for id := range rv.Bids {
+		if rv.Sealed == nil {
+			rv.Sealed = make(map[string]bool)
+		}
+		rv.Sealed[id] = true
+		if id != viewerID {
+			delete(rv.Bids, id)
+			delete(rv.BlindBids, id)
+		}
+	}
+}
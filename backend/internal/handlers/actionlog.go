@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/etanetan/up-and-down-the-river/backend/internal/game"
+)
+
+// LogHandler returns the log of every action accepted in a game, oldest first.
+// Session tokens are never included. Shuffle seeds, the game's own among them,
+// reveal every hand dealt, so they are only included once the game is finished.
+// Sealed bids are left out until they are revealed.
+func LogHandler(w http.ResponseWriter, r *http.Request) {
+	gameID := r.URL.Query().Get("gameId")
+	if gameID == "" {
+		http.Error(w, "gameId required", http.StatusBadRequest)
+		return
+	}
+	g, ok := game.GetGame(gameID)
+	if !ok {
+		http.Error(w, "game not found", http.StatusNotFound)
+		return
+	}
+	g.Lock()
+	finished := g.State == "finished"
+	cfg := g.Config
+	if !finished {
+		cfg.Seed = 0
+	}
+	entries := make([]game.LogEntry, len(g.Log))
+	for i, e := range g.Log {
+		e.Action.Token = ""
+		if !finished {
+			e.Action.Seed = 0
+		}
+		entries[i] = e
+	}
+	if g.BidsSealed() {
+		// Nothing but bids and looks happens between the deal and the reveal.
+		for i := len(entries) - 1; i >= 0; i-- {
+			a := &entries[i].Action
+			if a.Type != game.ActionBid && a.Type != game.ActionLook {
+				break
+			}
+			a.Bid = 0
+			a.Blind = false
+		}
+	}
+	g.Unlock()
+	resp := map[string]interface{}{
+		"gameId":  gameID,
+		"config":  cfg,
+		"entries": entries,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ReplayHandler returns the full record of a finished game: every hand as it
+// was dealt, the bids in order and each trick with its winner. With the
+// optional version parameter it instead returns the state, with every hand
+// face up, as it was after that many actions.
+func ReplayHandler(w http.ResponseWriter, r *http.Request) {
+	gameID := r.URL.Query().Get("gameId")
+	if gameID == "" {
+		http.Error(w, "gameId required", http.StatusBadRequest)
+		return
+	}
+	g, ok := game.GetGame(gameID)
+	if !ok {
+		http.Error(w, "game not found", http.StatusNotFound)
+		return
+	}
+	g.Lock()
+	defer g.Unlock()
+	if g.State != "finished" {
+		http.Error(w, "replays are only available once the game is finished", http.StatusConflict)
+		return
+	}
+	var resp interface{}
+	var err error
+	if s := r.URL.Query().Get("version"); s != "" {
+		version, convErr := strconv.Atoi(s)
+		if convErr != nil {
+			http.Error(w, "invalid version", http.StatusBadRequest)
+			return
+		}
+		resp, err = game.StateAt(g, version)
+	} else {
+		resp, err = game.BuildRecord(g)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
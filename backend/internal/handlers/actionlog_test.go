@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/etanetan/up-and-down-the-river/backend/internal/game"
+)
+
+func TestSealedBidsStayHidden(t *testing.T) {
+	w := post(CreateGameHandler, map[string]interface{}{
+		"displayName":     "Ann",
+		"creatorMaxCards": 3,
+		"sealedBids":      true,
+		"blindBids":       true,
+		"sequence":        game.SequenceDown,
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("create: %d %s", w.Code, w.Body)
+	}
+	var ann map[string]string
+	json.NewDecoder(w.Body).Decode(&ann)
+	gameID := ann["gameId"]
+	var bob map[string]string
+	json.NewDecoder(post(JoinGameHandler, map[string]string{"gameId": gameID, "displayName": "Bob"}).Body).Decode(&bob)
+	var cy map[string]string
+	json.NewDecoder(post(JoinGameHandler, map[string]string{"gameId": gameID, "displayName": "Cy"}).Body).Decode(&cy)
+	if w := post(StartGameHandler, map[string]string{"gameId": gameID, "token": ann["token"]}); w.Code != http.StatusOK {
+		t.Fatalf("start: %d %s", w.Code, w.Body)
+	}
+	if w := post(BidHandler, map[string]interface{}{"gameId": gameID, "token": ann["token"], "bid": 2, "blind": true}); w.Code != http.StatusOK {
+		t.Fatalf("bid: %d %s", w.Code, w.Body)
+	}
+	if w := post(BidHandler, map[string]interface{}{"gameId": gameID, "token": cy["token"], "bid": 0, "blind": true}); w.Code != http.StatusOK {
+		t.Fatalf("bid: %d %s", w.Code, w.Body)
+	}
+
+	w = httptest.NewRecorder()
+	LogHandler(w, httptest.NewRequest(http.MethodGet, "/?gameId="+gameID, nil))
+	var log struct {
+		Entries []game.LogEntry `json:"entries"`
+	}
+	json.NewDecoder(w.Body).Decode(&log)
+	for _, e := range log.Entries {
+		if e.Action.Type == game.ActionBid && (e.Action.Bid != 0 || e.Action.Blind) {
+			t.Errorf("log shows a sealed bid: %+v", e.Action)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	GetGameStateHandler(w, httptest.NewRequest(http.MethodGet, "/?gameId="+gameID+"&token="+bob["token"], nil))
+	var view game.GameView
+	json.NewDecoder(w.Body).Decode(&view)
+	round := view.CurrentRound
+	if _, ok := round.Bids[ann["playerId"]]; ok {
+		t.Errorf("another seat sees the sealed bid: %v", round.Bids)
+	}
+	if round.BlindBids[ann["playerId"]] {
+		t.Errorf("another seat sees that the sealed bid was blind: %v", round.BlindBids)
+	}
+	if !round.Sealed[ann["playerId"]] {
+		t.Errorf("another seat does not see that a bid is in: %v", round.Sealed)
+	}
+	for _, p := range view.Players {
+		if p.CurrentBid != 0 || p.BidOrder != 0 {
+			t.Errorf("another seat sees %s bid %d in position %d", p.ID, p.CurrentBid, p.BidOrder)
+		}
+	}
+}
@@ -2,11 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"math"
 	"math/rand"
 	"net/http"
-	"strings"
+	"strconv"
 	"time"
 
 	"github.com/etanetan/up-and-down-the-river/backend/internal/game"
@@ -16,7 +16,6 @@ import (
 const letterBytes = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
 
 func generateGameID() string {
-	rand.Seed(time.Now().UnixNano())
 	letters := make([]byte, 3)
 	for i := range letters {
 		letters[i] = letterBytes[rand.Intn(len(letterBytes))]
@@ -25,9 +24,58 @@ func generateGameID() string {
 	return string(letters) + fmt.Sprintf("%03d", numbers)
 }
 
+// maxIDAttempts is how many game IDs addNewGame tries before giving up.
+const maxIDAttempts = 10
+
+// newGameID draws the ID for a new game; tests replace it to force collisions.
+var newGameID = generateGameID
+
+// addNewGame stores and registers a game made by build for a freshly
+// generated ID, trying another ID if the one drawn is already taken.
+// Errors from build are returned as they are; failures to store the game
+// are wrapped in errNotSaved.
+func addNewGame(build func(id string) (*game.Game, error)) (*game.Game, error) {
+	for attempt := 1; ; attempt++ {
+		g, err := build(newGameID())
+		if err != nil {
+			return nil, err
+		}
+		g.Lock()
+		err = game.SaveGame(g, 0)
+		g.Unlock()
+		if err == nil {
+			game.AddGame(g)
+			return g, nil
+		}
+		if !errors.Is(err, game.ErrVersionConflict) || attempt == maxIDAttempts {
+			return nil, fmt.Errorf("%w: %w", errNotSaved, err)
+		}
+	}
+}
+
+// authenticate returns the player in g holding token.
+// If no seat matches it writes an error response and returns nil.
+func authenticate(w http.ResponseWriter, g *game.Game, token string) *game.Player {
+	if token == "" {
+		http.Error(w, "token is required", http.StatusUnauthorized)
+		return nil
+	}
+	g.Lock()
+	p, _ := game.FindPlayerByToken(g, token)
+	g.Unlock()
+	if p == nil {
+		http.Error(w, "invalid token for this game", http.StatusForbidden)
+		return nil
+	}
+	return p
+}
+
 // CreateGameHandler creates a new game and adds the creator.
+// The request carries the creator's display name alongside the game.Config
+// options; creatorMaxCards, when given, sets maxCards.
 func CreateGameHandler(w http.ResponseWriter, r *http.Request) {
 	var req struct {
+		game.Config
 		DisplayName     string `json:"displayName"`
 		CreatorMaxCards int    `json:"creatorMaxCards"`
 	}
@@ -39,24 +87,34 @@ func CreateGameHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "displayName is required", http.StatusBadRequest)
 		return
 	}
-	gameID := generateGameID()
-
-	creator := &game.Player{
-		ID:          uuid.New().String(),
-		DisplayName: req.DisplayName,
-	}
-	game.GamesMu.Lock()
-	defer game.GamesMu.Unlock()
-	newGame := &game.Game{
-		ID:              gameID,
-		Players:         []*game.Player{creator},
-		State:           "lobby",
-		CreatorMaxCards: req.CreatorMaxCards,
-	}
-	game.Games[gameID] = newGame
+	cfg := req.Config
+	if req.CreatorMaxCards != 0 {
+		cfg.MaxCards = req.CreatorMaxCards
+	}
+	if err := cfg.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if cfg.Seed == 0 {
+		// Every game gets its own seed, so its rounds can be dealt again.
+		cfg.Seed = rand.Int63()
+	}
+	token := uuid.New().String()
+	newGame, err := addNewGame(func(id string) (*game.Game, error) {
+		cfg.ID = id
+		g := game.NewGame(cfg)
+		_, err := g.Apply(game.Action{Type: game.ActionJoin, DisplayName: req.DisplayName, Token: token, Time: time.Now()})
+		return g, err
+	})
+	if err != nil {
+		writeMoveError(w, err)
+		return
+	}
+	gameID := newGame.ID
 	resp := map[string]string{
 		"gameId":   gameID,
-		"playerId": creator.ID,
+		"playerId": game.SeatHandle(0),
+		"token":    token,
 		"link":     fmt.Sprintf("http://%s/games/%s", r.Host, gameID),
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -77,31 +135,23 @@ func JoinGameHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "gameId and displayName are required", http.StatusBadRequest)
 		return
 	}
-	game.GamesMu.Lock()
-	g, ok := game.Games[req.GameID]
-	game.GamesMu.Unlock()
+	g, ok := game.GetGame(req.GameID)
 	if !ok {
 		http.Error(w, "game not found", http.StatusNotFound)
 		return
 	}
-	if g.State != "lobby" {
-		http.Error(w, "game already started", http.StatusBadRequest)
+	token := uuid.New().String()
+	g.Lock()
+	defer g.Unlock()
+	if err := applyAction(g, game.Action{Type: game.ActionJoin, DisplayName: req.DisplayName, Token: token}); err != nil {
+		writeMoveError(w, err)
 		return
 	}
-	if len(g.Players) >= 6 {
-		http.Error(w, "game is full", http.StatusBadRequest)
-		return
-	}
-	newPlayer := &game.Player{
-		ID:          uuid.New().String(),
-		DisplayName: req.DisplayName,
-	}
-	game.GamesMu.Lock()
-	g.Players = append(g.Players, newPlayer)
-	game.GamesMu.Unlock()
+	newPlayer := g.Players[len(g.Players)-1]
 	resp := map[string]string{
 		"gameId":   req.GameID,
 		"playerId": newPlayer.ID,
+		"token":    token,
 	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
@@ -111,170 +161,143 @@ func JoinGameHandler(w http.ResponseWriter, r *http.Request) {
 func StartGameHandler(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		GameID string `json:"gameId"`
+		Token  string `json:"token"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	game.GamesMu.Lock()
-	g, ok := game.Games[req.GameID]
-	game.GamesMu.Unlock()
+	g, ok := game.GetGame(req.GameID)
 	if !ok {
 		http.Error(w, "game not found", http.StatusNotFound)
 		return
 	}
-	if g.State != "lobby" {
-		http.Error(w, "game already started", http.StatusBadRequest)
-		return
-	}
-	if len(g.Players) < 2 {
-		http.Error(w, "need at least 2 players to start", http.StatusBadRequest)
-		return
-	}
-	// Determine maximum cards per round.
-	maxPossible := int(math.Floor(54.0 / float64(len(g.Players))))
-	desired := g.CreatorMaxCards
-	if desired <= 0 || desired > maxPossible {
-		desired = maxPossible
-	}
-	g.RoundSequence = game.ComputeRoundSequence(desired)
-	g.CurrentRoundIndex = 0
-	// Randomly choose a dealer.
-	dealerIndex := rand.Intn(len(g.Players))
-	round := &game.Round{
-		RoundNumber:    g.CurrentRoundIndex + 1,
-		TotalCards:     g.RoundSequence[g.CurrentRoundIndex],
-		DealerIndex:    dealerIndex,
-		Bids:           make(map[string]int),
-		BidOrder:       []string{},
-		CurrentBidTurn: 0,
-	}
-	g.CurrentRound = round
-	g.State = "bidding"
-	// Deal cards.
-	deck := game.CreateDeck()
-	game.ShuffleDeck(deck)
-	for _, p := range g.Players {
-		p.Hand = []game.Card{}
-		p.CurrentBid = 0
-		p.TricksWon = 0
-	}
-	if err := game.DealCards(deck, g.Players, round.TotalCards); err != nil {
-		http.Error(w, "error dealing cards: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
-	// Set bidding order: start with the player to the left of the dealer, then dealer last.
-	n := len(g.Players)
-	var biddingOrder []string
-	for i := 1; i < n; i++ {
-		index := (dealerIndex + i) % n
-		biddingOrder = append(biddingOrder, g.Players[index].ID)
-	}
-	biddingOrder = append(biddingOrder, g.Players[dealerIndex].ID)
-	round.BidOrder = biddingOrder
-	round.CurrentBidTurn = 0
+	caller := authenticate(w, g, req.Token)
+	if caller == nil {
+		return
+	}
+	g.Lock()
+	defer g.Unlock()
+	if err := applyAction(g, game.Action{Type: game.ActionStart, PlayerID: caller.ID}); err != nil {
+		writeMoveError(w, err)
+		return
+	}
+	view := game.NewGameView(g, caller.ID)
 	resp := map[string]interface{}{
 		"message":       "Game started; bidding phase begins",
 		"gameId":        g.ID,
-		"currentRound":  round,
-		"biddingOrder":  biddingOrder,
-		"players":       g.Players,
-		"roundSequence": g.RoundSequence,
+		"currentRound":  view.CurrentRound,
+		"biddingOrder":  view.CurrentRound.BidOrder,
+		"players":       view.Players,
+		"roundSequence": view.RoundSequence,
 	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
 
-// BidHandler accepts a bid from a player.
-func BidHandler(w http.ResponseWriter, r *http.Request) {
+// errNotSaved marks an accepted action whose result could not be stored.
+var errNotSaved = errors.New("game could not be saved")
+
+// applyAction applies a to g, saves the new version of g and pushes the
+// resulting events to subscribers. When the game enters a trick or round
+// review, the review is scheduled to end after the game's review delay.
+// If g cannot be saved, the change is dropped along with the in-memory copy.
+// It is shared by the HTTP handlers and the WebSocket channel.
+// The caller must hold g's lock.
+func applyAction(g *game.Game, a game.Action) error {
+	version := g.Version
+	a.Time = time.Now()
+	events, err := g.Apply(a)
+	if err != nil {
+		return err
+	}
+	startReview := g.InReview() && g.ReviewDeadline.IsZero() && g.ReviewDelayMs > 0
+	if startReview {
+		g.ReviewDeadline = time.Now().Add(time.Duration(g.ReviewDelayMs) * time.Millisecond)
+	}
+	if err := game.SaveGame(g, version); err != nil {
+		forgetGame(g)
+		return fmt.Errorf("%w: %w", errNotSaved, err)
+	}
+	if startReview {
+		scheduleReviewEnd(g)
+	}
+	broadcast(g, events...)
+	return nil
+}
+
+// writeMoveError reports an error from applyAction as an HTTP error.
+func writeMoveError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, game.ErrVersionConflict) {
+		status = http.StatusConflict
+	} else if errors.Is(err, errNotSaved) {
+		status = http.StatusInternalServerError
+	}
+	http.Error(w, err.Error(), status)
+}
+
+// LookHandler turns a player's face-down hand over, in a game with blind bids.
+// It answers with the player's view, which now includes their hand.
+func LookHandler(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		GameID   string `json:"gameId"`
-		PlayerID string `json:"playerId"`
-		Bid      int    `json:"bid"`
+		GameID string `json:"gameId"`
+		Token  string `json:"token"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	game.GamesMu.Lock()
-	g, ok := game.Games[req.GameID]
-	game.GamesMu.Unlock()
+	g, ok := game.GetGame(req.GameID)
 	if !ok {
 		http.Error(w, "game not found", http.StatusNotFound)
 		return
 	}
-	if g.State != "bidding" {
-		http.Error(w, "not in bidding phase", http.StatusBadRequest)
+	player := authenticate(w, g, req.Token)
+	if player == nil {
 		return
 	}
-	round := g.CurrentRound
-	// Ensure it is the player's turn.
-	currentBidderID := round.BidOrder[round.CurrentBidTurn]
-	if currentBidderID != req.PlayerID {
-		http.Error(w, "not your turn to bid", http.StatusBadRequest)
+	g.Lock()
+	defer g.Unlock()
+	if err := applyAction(g, game.Action{Type: game.ActionLook, PlayerID: player.ID}); err != nil {
+		writeMoveError(w, err)
 		return
 	}
-	if req.Bid < 0 || req.Bid > round.TotalCards {
-		http.Error(w, "invalid bid amount", http.StatusBadRequest)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(game.NewGameView(g, player.ID))
+}
+
+// BidHandler accepts a bid from a player. With blind set, the bid is made
+// without looking at the hand.
+func BidHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		GameID string `json:"gameId"`
+		Token  string `json:"token"`
+		Bid    int    `json:"bid"`
+		Blind  bool   `json:"blind"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	// If the bidder is the dealer (last bidder) and the round has more than one card, enforce the restriction.
-	isDealer := (round.BidOrder[round.CurrentBidTurn] == g.Players[round.DealerIndex].ID)
-	if isDealer && round.TotalCards > 1 {
-		sumBids := 0
-		for _, b := range round.Bids {
-			sumBids += b
-		}
-		if sumBids+req.Bid == round.TotalCards {
-			http.Error(w, "dealer bid cannot make total bids equal total cards", http.StatusBadRequest)
-			return
-		}
+	g, ok := game.GetGame(req.GameID)
+	if !ok {
+		http.Error(w, "game not found", http.StatusNotFound)
+		return
 	}
-	round.Bids[req.PlayerID] = req.Bid
-	for _, p := range g.Players {
-		if p.ID == req.PlayerID {
-			p.CurrentBid = req.Bid
-			p.BidOrder = round.CurrentBidTurn
-			break
-		}
+	bidder := authenticate(w, g, req.Token)
+	if bidder == nil {
+		return
 	}
-	round.CurrentBidTurn++
-	if round.CurrentBidTurn >= len(round.BidOrder) {
-		g.State = "playing"
-		highestBid := -1
-		leaderID := ""
-		leaderOrder := len(g.Players) + 1
-		for pid, bid := range round.Bids {
-			var order int
-			for _, p := range g.Players {
-				if p.ID == pid {
-					order = p.BidOrder
-					break
-				}
-			}
-			if bid > highestBid || (bid == highestBid && order < leaderOrder) {
-				highestBid = bid
-				leaderID = pid
-				leaderOrder = order
-			}
-		}
-		leaderIndex := 0
-		for i, p := range g.Players {
-			if p.ID == leaderID {
-				leaderIndex = i
-				break
-			}
-		}
-		round.CurrentTrick = &game.Trick{
-			LeaderID: leaderID,
-			Plays:    []game.Play{},
-		}
-		round.TrickTurnIndex = 0
-		round.TrickLeader = leaderIndex
+	g.Lock()
+	defer g.Unlock()
+	if err := applyAction(g, game.Action{Type: game.ActionBid, PlayerID: bidder.ID, Bid: req.Bid, Blind: req.Blind}); err != nil {
+		writeMoveError(w, err)
+		return
 	}
 	resp := map[string]interface{}{
 		"message": "Bid accepted",
-		"bids":    round.Bids,
+		"bids":    game.NewGameView(g, bidder.ID).CurrentRound.Bids,
 	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
@@ -283,236 +306,167 @@ func BidHandler(w http.ResponseWriter, r *http.Request) {
 // PlayHandler processes a card played by a player.
 func PlayHandler(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		GameID   string    `json:"gameId"`
-		PlayerID string    `json:"playerId"`
-		Card     game.Card `json:"card"`
+		GameID string    `json:"gameId"`
+		Token  string    `json:"token"`
+		Card   game.Card `json:"card"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	game.GamesMu.Lock()
-	g, ok := game.Games[req.GameID]
-	game.GamesMu.Unlock()
+	g, ok := game.GetGame(req.GameID)
 	if !ok {
 		http.Error(w, "game not found", http.StatusNotFound)
 		return
 	}
 
-	if g.State != "playing" {
-		http.Error(w, "not in playing phase", http.StatusBadRequest)
+	player := authenticate(w, g, req.Token)
+	if player == nil {
 		return
 	}
 
-	round := g.CurrentRound
-	expectedPlayerID := g.Players[(round.TrickLeader+round.TrickTurnIndex)%len(g.Players)].ID
-	if req.PlayerID != expectedPlayerID {
-		http.Error(w, "not your turn to play", http.StatusBadRequest)
+	g.Lock()
+	defer g.Unlock()
+	if err := applyAction(g, game.Action{Type: game.ActionPlay, PlayerID: player.ID, Card: req.Card}); err != nil {
+		writeMoveError(w, err)
 		return
 	}
 
-	player, _ := game.FindPlayer(g, req.PlayerID)
-	cardIndex := -1
-	for i, card := range player.Hand {
-		if game.CardEquals(card, req.Card) {
-			cardIndex = i
-			break
+	view := game.NewGameView(g, player.ID)
+	_, seat := game.FindPlayer(g, player.ID)
+	resp := map[string]interface{}{
+		"message":      "Card played",
+		"currentTrick": view.CurrentRound.CurrentTrick,
+		"tricks":       view.CurrentRound.Tricks,
+		"playerHand":   view.Players[seat].Hand,
+	}
+	if g.InReview() {
+		trick := view.CurrentRound.CurrentTrick
+		for _, p := range trick.Plays {
+			if p.PlayerID == trick.WinnerID {
+				resp["winningCard"] = p.Card
+			}
 		}
+		resp["trickOverMessage"] = view.TrickOverMessage
 	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
 
-	if cardIndex == -1 {
-		http.Error(w, "player does not have that card", http.StatusBadRequest)
-		return
+// AckHandler records that a player has seen the trick or round under review.
+// Play moves on as soon as every seat has acknowledged it.
+func AckHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		GameID string `json:"gameId"`
+		Token  string `json:"token"`
 	}
-
-	playedCard := player.Hand[cardIndex]
-	player.Hand = append(player.Hand[:cardIndex], player.Hand[cardIndex+1:]...)
-
-	// Enforce follow-suit.
-	if len(round.CurrentTrick.Plays) > 0 {
-		leadCard := round.CurrentTrick.Plays[0].Card
-		leadSuit := strings.ToLower(leadCard.Suit)
-		hasLeadSuit := false
-		for _, c := range player.Hand {
-			// A non-joker card is defined as having Rank <= Ace (14).
-			if c.Rank <= 14 && strings.ToLower(c.Suit) == leadSuit {
-				hasLeadSuit = true
-				break
-			}
-		}
-		if hasLeadSuit {
-			// If the player holds a non-joker card of the lead suit,
-			// they must follow suit (i.e. play a card with suit matching the lead).
-			// Also, playing a joker (Rank > 14) is not allowed if they can follow suit.
-			if req.Card.Rank > 14 || strings.ToLower(req.Card.Suit) != leadSuit {
-				http.Error(w, "you must follow suit", http.StatusBadRequest)
-				return
-			}
-		}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
-
-	play := game.Play{
-		PlayerID: req.PlayerID,
-		Card:     playedCard,
+	g, ok := game.GetGame(req.GameID)
+	if !ok {
+		http.Error(w, "game not found", http.StatusNotFound)
+		return
 	}
-	round.CurrentTrick.Plays = append(round.CurrentTrick.Plays, play)
-	round.TrickTurnIndex++
-
-	if len(round.CurrentTrick.Plays) == len(g.Players) {
-		leadSuit := strings.ToLower(round.CurrentTrick.Plays[0].Card.Suit)
-		winningPlay := round.CurrentTrick.Plays[0]
-		for _, p := range round.CurrentTrick.Plays[1:] {
-			if game.CompareCards(p.Card, winningPlay.Card, leadSuit) > 0 {
-				winningPlay = p
-			}
-		}
-		round.CurrentTrick.WinnerID = winningPlay.PlayerID
-
-		var winningMessage string = "Trick is over"
-		if winner, _ := game.FindPlayer(g, winningPlay.PlayerID); winner != nil {
-			winner.TricksWon++
-			winningMessage = fmt.Sprintf("%s won the trick!", winner.DisplayName)
-		}
-
-		g.TrickOverMessage = winningMessage
-
-		round.Tricks = append(round.Tricks, *round.CurrentTrick)
-
-		resp := map[string]interface{}{
-			"message":          "Card played",
-			"currentTrick":     round.CurrentTrick,
-			"tricks":           round.Tricks,
-			"winningCard":      winningPlay.Card,
-			"trickOverMessage": winningMessage,
-			"playerHand":       player.Hand,
-		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(resp)
-
-		go func() {
-			time.Sleep(2000 * time.Millisecond)
-			game.GamesMu.Lock()
-			defer game.GamesMu.Unlock()
-
-			g.TrickOverMessage = ""
-
-			if len(g.Players[0].Hand) > 0 {
-				var winnerIndex int
-				for i, p := range g.Players {
-					if p.ID == winningPlay.PlayerID {
-						winnerIndex = i
-						break
-					}
-				}
-				round.TrickLeader = winnerIndex
-				round.CurrentTrick = &game.Trick{
-					LeaderID: winningPlay.PlayerID,
-					Plays:    []game.Play{},
-				}
-				round.TrickTurnIndex = 0
-			} else {
-				var roundResults []game.PlayerRoundResult
-				for _, p := range g.Players {
-					bid := round.Bids[p.ID]
-					roundScore := 0
-					if p.TricksWon == bid {
-						roundScore = 10 + bid*bid
-						p.Score += roundScore
-					}
-					roundResults = append(roundResults, game.PlayerRoundResult{
-						PlayerID:   p.ID,
-						Bid:        bid,
-						TricksWon:  p.TricksWon,
-						RoundScore: roundScore,
-					})
-				}
-
-				newRoundResult := game.RoundResult{
-					RoundNumber: round.RoundNumber,
-					TotalCards:  round.TotalCards,
-					Results:     roundResults,
-				}
-				g.RoundResults = append(g.RoundResults, newRoundResult)
-
-				g.CurrentRoundIndex++
-				if g.CurrentRoundIndex < len(g.RoundSequence) {
-					newDealerIndex := (round.DealerIndex + 1) % len(g.Players)
-					for _, p := range g.Players {
-						p.TricksWon = 0
-						p.Hand = []game.Card{}
-					}
-
-					newRound := &game.Round{
-						RoundNumber:    g.CurrentRoundIndex + 1,
-						TotalCards:     g.RoundSequence[g.CurrentRoundIndex],
-						DealerIndex:    newDealerIndex,
-						Bids:           make(map[string]int),
-						BidOrder:       []string{},
-						CurrentBidTurn: 0,
-						Tricks:         []game.Trick{},
-					}
-
-					n := len(g.Players)
-					for i := 1; i < n; i++ {
-						index := (newDealerIndex + i) % n
-						newRound.BidOrder = append(newRound.BidOrder, g.Players[index].ID)
-					}
-					newRound.BidOrder = append(newRound.BidOrder, g.Players[newDealerIndex].ID)
-
-					deck := game.CreateDeck()
-					game.ShuffleDeck(deck)
-					if err := game.DealCards(deck, g.Players, newRound.TotalCards); err != nil {
-						return
-					}
-					g.CurrentRound = newRound
-					g.State = "bidding"
-				} else {
-					g.State = "finished"
-					time.Sleep(2000 * time.Millisecond)
-					for _, p := range g.Players {
-						missedRounds := 0
-						for _, roundResult := range g.RoundResults {
-							for _, res := range roundResult.Results {
-								if res.PlayerID == p.ID && res.TricksWon != res.Bid {
-									missedRounds++
-								}
-							}
-						}
-						p.MissedBids = missedRounds
-					}
-				}
-			}
-		}()
+	player := authenticate(w, g, req.Token)
+	if player == nil {
 		return
 	}
-
-	resp := map[string]interface{}{
-		"message":      "Card played",
-		"currentTrick": round.CurrentTrick,
-		"tricks":       round.Tricks,
-		"playerHand":   player.Hand,
+	g.Lock()
+	defer g.Unlock()
+	if err := applyAction(g, game.Action{Type: game.ActionAck, PlayerID: player.ID}); err != nil {
+		writeMoveError(w, err)
+		return
 	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
+	json.NewEncoder(w).Encode(game.NewGameView(g, player.ID))
 }
 
-// GetGameStateHandler returns the current game state.
+// longPollTimeout is the longest GetGameStateHandler waits for a requested version.
+const longPollTimeout = 25 * time.Second
+
+// GetGameStateHandler returns the game state as seen by the requesting player.
+// The optional token query parameter selects whose hand is visible;
+// without it the caller gets a spectator view.
+// The response carries the game version as its ETag, and a matching
+// If-None-Match header is answered with 304 Not Modified. With the optional
+// waitForVersion parameter the request is held until the game reaches that
+// version or longPollTimeout expires, whichever comes first.
 func GetGameStateHandler(w http.ResponseWriter, r *http.Request) {
 	gameID := r.URL.Query().Get("gameId")
+	token := r.URL.Query().Get("token")
 	if gameID == "" {
 		http.Error(w, "gameId required", http.StatusBadRequest)
 		return
 	}
-	game.GamesMu.Lock()
-	g, ok := game.Games[gameID]
-	game.GamesMu.Unlock()
+	var waitFor int
+	if s := r.URL.Query().Get("waitForVersion"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			http.Error(w, "invalid waitForVersion", http.StatusBadRequest)
+			return
+		}
+		waitFor = v
+	}
+	g, ok := game.GetGame(gameID)
 	if !ok {
 		http.Error(w, "game not found", http.StatusNotFound)
 		return
 	}
+	g.Lock()
+	var viewerID string
+	if p, _ := game.FindPlayerByToken(g, token); p != nil {
+		viewerID = p.ID
+	}
+	if g.Version < waitFor {
+		g.Unlock()
+		waitForVersion(r, g, waitFor)
+		g.Lock()
+	}
+	view := game.NewGameView(g, viewerID)
+	g.Unlock()
+
+	etag := fmt.Sprintf("\"%d\"", view.Version)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(g)
+	json.NewEncoder(w).Encode(view)
+}
+
+// waitForVersion blocks until g reaches version, longPollTimeout expires
+// or the client goes away.
+func waitForVersion(r *http.Request, g *game.Game, version int) {
+	timeout := time.NewTimer(longPollTimeout)
+	defer timeout.Stop()
+	for {
+		g.Lock()
+		if g.Version >= version {
+			g.Unlock()
+			return
+		}
+		sub := subscribe(g, "")
+		g.Unlock()
+		select {
+		case <-r.Context().Done():
+		case <-timeout.C:
+		case <-sub.send:
+			// Any update means the version moved; unsubscribe and check again.
+			g.Lock()
+			unsubscribe(g, sub)
+			g.Unlock()
+			continue
+		}
+		g.Lock()
+		unsubscribe(g, sub)
+		g.Unlock()
+		return
+	}
 }
 
 // ResetGameHandler resets the game state so that it looks like a freshly started game.
@@ -522,68 +476,28 @@ func GetGameStateHandler(w http.ResponseWriter, r *http.Request) {
 func ResetGameHandler(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		GameID string `json:"gameId"`
+		Token  string `json:"token"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	game.GamesMu.Lock()
-	defer game.GamesMu.Unlock()
-
-	g, ok := game.Games[req.GameID]
+	g, ok := game.GetGame(req.GameID)
 	if !ok {
 		http.Error(w, "game not found", http.StatusNotFound)
 		return
 	}
-
-	g.RoundResults = []game.RoundResult{}
-	g.CurrentRoundIndex = 0
-	for _, p := range g.Players {
-		p.Hand = []game.Card{}
-		p.Score = 0
-		p.TricksWon = 0
-		p.CurrentBid = 0
-		p.MissedBids = 0
+	caller := authenticate(w, g, req.Token)
+	if caller == nil {
+		return
 	}
-
-	maxPossible := int(math.Floor(54.0 / float64(len(g.Players))))
-	desired := g.CreatorMaxCards
-	if desired <= 0 || desired > maxPossible {
-		desired = maxPossible
-	}
-	g.RoundSequence = game.ComputeRoundSequence(desired)
-	g.State = "bidding"
-	var dealerIndex int
-	if g.CurrentRound != nil {
-		dealerIndex = (g.CurrentRound.DealerIndex + 1) % len(g.Players)
-	} else {
-		dealerIndex = rand.Intn(len(g.Players))
-	}
-	newRound := &game.Round{
-		RoundNumber:    1,
-		TotalCards:     g.RoundSequence[0],
-		DealerIndex:    dealerIndex,
-		Bids:           make(map[string]int),
-		BidOrder:       []string{},
-		CurrentBidTurn: 0,
-	}
-	n := len(g.Players)
-	for i := 1; i < n; i++ {
-		index := (dealerIndex + i) % n
-		newRound.BidOrder = append(newRound.BidOrder, g.Players[index].ID)
-	}
-	newRound.BidOrder = append(newRound.BidOrder, g.Players[dealerIndex].ID)
-	g.CurrentRound = newRound
-	deck := game.CreateDeck()
-	game.ShuffleDeck(deck)
-	for _, p := range g.Players {
-		p.Hand = []game.Card{}
-	}
-	if err := game.DealCards(deck, g.Players, newRound.TotalCards); err != nil {
-		http.Error(w, "error dealing cards: "+err.Error(), http.StatusInternalServerError)
+	g.Lock()
+	defer g.Unlock()
+	if err := applyAction(g, game.Action{Type: game.ActionReset, PlayerID: caller.ID}); err != nil {
+		writeMoveError(w, err)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(g)
+	json.NewEncoder(w).Encode(game.NewGameView(g, caller.ID))
 }
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/etanetan/up-and-down-the-river/backend/internal/game"
+)
+
+// post sends body as JSON to h and returns the recorded response.
+func post(h http.HandlerFunc, body interface{}) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload)))
+	return w
+}
+
+// TestConcurrentActions has every seat of one game race through the HTTP
+// handlers at once, alongside state polls, an event stream and review timers,
+// and checks that the game still ends in a state its log reproduces.
+// Run it with -race.
+func TestConcurrentActions(t *testing.T) {
+	w := post(CreateGameHandler, map[string]interface{}{
+		"displayName":     "Ann",
+		"creatorMaxCards": 3,
+		"reviewDelayMs":   1,
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("create: %d %s", w.Code, w.Body)
+	}
+	var created map[string]string
+	json.NewDecoder(w.Body).Decode(&created)
+	gameID := created["gameId"]
+	tokens := map[string]string{created["playerId"]: created["token"]}
+	for _, name := range []string{"Bob", "Cy", "Di"} {
+		w := post(JoinGameHandler, map[string]string{"gameId": gameID, "displayName": name})
+		if w.Code != http.StatusOK {
+			t.Fatalf("join: %d %s", w.Code, w.Body)
+		}
+		var joined map[string]string
+		json.NewDecoder(w.Body).Decode(&joined)
+		tokens[joined["playerId"]] = joined["token"]
+	}
+	g, ok := game.GetGame(gameID)
+	if !ok {
+		t.Fatal("game not found")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var watchers sync.WaitGroup
+	watchers.Add(1)
+	go func() {
+		defer watchers.Done()
+		req := httptest.NewRequest(http.MethodGet, "/?gameId="+gameID+"&token="+tokens[game.SeatHandle(1)], nil)
+		EventsHandler(httptest.NewRecorder(), req.WithContext(ctx))
+	}()
+	for seat := range tokens {
+		watchers.Add(1)
+		go func(token string) {
+			defer watchers.Done()
+			for ctx.Err() == nil {
+				GetGameStateHandler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?gameId="+gameID+"&token="+token, nil))
+			}
+		}(tokens[seat])
+	}
+
+	deadline := time.Now().Add(30 * time.Second)
+	var players sync.WaitGroup
+	var seed int64
+	for seat, token := range tokens {
+		// Two goroutines per seat, so the same move is often sent twice.
+		for i := 0; i < 2; i++ {
+			seed++
+			players.Add(1)
+			go func(seat, token string, rng *rand.Rand) {
+				defer players.Done()
+				for time.Now().Before(deadline) {
+					g.Lock()
+					finished := g.State == "finished"
+					actions := g.LegalActions(seat)
+					g.Unlock()
+					if finished {
+						return
+					}
+					if len(actions) == 0 {
+						time.Sleep(time.Millisecond)
+						continue
+					}
+					a := actions[rng.Intn(len(actions))]
+					switch a.Type {
+					case game.ActionStart:
+						post(StartGameHandler, map[string]string{"gameId": gameID, "token": token})
+					case game.ActionLook:
+						post(LookHandler, map[string]string{"gameId": gameID, "token": token})
+					case game.ActionBid:
+						post(BidHandler, map[string]interface{}{"gameId": gameID, "token": token, "bid": a.Bid, "blind": a.Blind})
+					case game.ActionPlay:
+						post(PlayHandler, map[string]interface{}{"gameId": gameID, "token": token, "card": a.Card})
+					case game.ActionAck:
+						post(AckHandler, map[string]string{"gameId": gameID, "token": token})
+					}
+				}
+			}(seat, token, rand.New(rand.NewSource(seed)))
+		}
+	}
+	players.Wait()
+	cancel()
+	watchers.Wait()
+
+	g.Lock()
+	defer g.Unlock()
+	if g.State != "finished" {
+		t.Fatalf("game did not finish: state %s at version %d", g.State, g.Version)
+	}
+	if g.Version != len(g.Log) {
+		t.Fatalf("version %d but %d log entries", g.Version, len(g.Log))
+	}
+	for i, e := range g.Log {
+		if e.Version != i+1 {
+			t.Fatalf("log entry %d has version %d", i, e.Version)
+		}
+	}
+	r, err := game.Replay(g.Config, g.Log)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	for i, p := range g.Players {
+		if r.Players[i].Score != p.Score {
+			t.Errorf("%s scored %d, replay gives %d", p.ID, p.Score, r.Players[i].Score)
+		}
+	}
+}
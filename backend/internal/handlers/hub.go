@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"sync"
+
+	"github.com/etanetan/up-and-down-the-river/backend/internal/game"
+)
+
+// update is one event together with the game state it produced,
+// redacted for the seat receiving it. Last marks the final event of an action.
+type update struct {
+	Event game.Event
+	State *game.GameView
+	Last  bool
+}
+
+// subscriber receives updates for one game on behalf of one seat.
+type subscriber struct {
+	playerID string
+	send     chan update
+}
+
+// historyLimit is about how many recent events are kept per game for clients
+// catching up. Only whole actions are kept, so a client either gets every event
+// it missed or learns that it has to start over.
+const historyLimit = 256
+
+// Subscribers and recent events per game ID.
+// The maps are shared by all games and guarded by hubMu; the functions below
+// also expect the lock of the game they are called for to be held, so that
+// what a subscriber sees always matches the order of changes to the game.
+var (
+	hubMu       sync.Mutex
+	subscribers = make(map[string]map[*subscriber]bool)
+	history     = make(map[string][]game.Event)
+)
+
+// subscribe registers a new subscriber for g seen from playerID.
+// The caller must hold g's lock.
+func subscribe(g *game.Game, playerID string) *subscriber {
+	hubMu.Lock()
+	defer hubMu.Unlock()
+	sub := &subscriber{playerID: playerID, send: make(chan update, 32)}
+	if subscribers[g.ID] == nil {
+		subscribers[g.ID] = make(map[*subscriber]bool)
+	}
+	subscribers[g.ID][sub] = true
+	return sub
+}
+
+// unsubscribe removes sub from g and closes its channel.
+// It is safe to call more than once. The caller must hold g's lock.
+func unsubscribe(g *game.Game, sub *subscriber) {
+	hubMu.Lock()
+	defer hubMu.Unlock()
+	removeSubscriber(g, sub)
+}
+
+// removeSubscriber is unsubscribe for callers already holding hubMu.
+func removeSubscriber(g *game.Game, sub *subscriber) {
+	if !subscribers[g.ID][sub] {
+		return
+	}
+	delete(subscribers[g.ID], sub)
+	close(sub.send)
+	if len(subscribers[g.ID]) == 0 {
+		delete(subscribers, g.ID)
+	}
+}
+
+// broadcast numbers events with the version of g they produced and pushes each
+// of them, along with the current state, to every subscriber of g.
+// Subscribers that have fallen too far behind are dropped.
+// The caller must hold g's lock.
+func broadcast(g *game.Game, events ...game.Event) {
+	hubMu.Lock()
+	defer hubMu.Unlock()
+	for i, ev := range events {
+		ev.Seq = g.Version
+		history[g.ID] = append(history[g.ID], ev)
+		if kept := history[g.ID]; len(kept) > historyLimit {
+			cut := len(kept) - historyLimit
+			for cut < len(kept) && kept[cut].Seq == kept[cut-1].Seq {
+				cut++
+			}
+			history[g.ID] = kept[cut:]
+		}
+		for sub := range subscribers[g.ID] {
+			select {
+			case sub.send <- update{Event: ev, State: game.NewGameView(g, sub.playerID), Last: i == len(events)-1}:
+			default:
+				removeSubscriber(g, sub)
+			}
+		}
+	}
+}
+
+// eventsSince returns the retained events of g produced after version seq.
+// Events older than the last historyLimit, or from before g was last loaded
+// from the store, are no longer available; ok is false if any of those
+// would have been returned.
+// The caller must hold g's lock.
+func eventsSince(g *game.Game, seq int) (events []game.Event, ok bool) {
+	hubMu.Lock()
+	defer hubMu.Unlock()
+	kept := history[g.ID]
+	if len(kept) == 0 {
+		return nil, seq >= g.Version
+	}
+	if seq < kept[0].Seq-1 {
+		return nil, false
+	}
+	for _, ev := range kept {
+		if ev.Seq > seq {
+			events = append(events, ev)
+		}
+	}
+	return events, true
+}
+
+// forgetGame drops g from the games in play along with its retained events.
+// The caller must hold g's lock.
+func forgetGame(g *game.Game) {
+	game.ForgetGame(g)
+	hubMu.Lock()
+	defer hubMu.Unlock()
+	delete(history, g.ID)
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/etanetan/up-and-down-the-river/backend/internal/game"
+	"github.com/google/uuid"
+)
+
+// maxRecordSize bounds the size of an imported game record.
+const maxRecordSize = 1 << 20
+
+// ExportHandler returns the game record of a finished game in plain text.
+// Records show every hand and seed, so games still being played are refused.
+func ExportHandler(w http.ResponseWriter, r *http.Request) {
+	gameID := r.URL.Query().Get("gameId")
+	if gameID == "" {
+		http.Error(w, "gameId required", http.StatusBadRequest)
+		return
+	}
+	g, ok := game.GetGame(gameID)
+	if !ok {
+		http.Error(w, "game not found", http.StatusNotFound)
+		return
+	}
+	g.Lock()
+	defer g.Unlock()
+	if g.State != "finished" {
+		http.Error(w, "only finished games can be exported", http.StatusForbidden)
+		return
+	}
+	var buf bytes.Buffer
+	if err := game.WriteRecord(&buf, g); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+g.ID+".txt\"")
+	w.Write(buf.Bytes())
+}
+
+// ImportHandler creates a new game from a game record posted as the request body.
+// The game gets a new ID, and the session token of every seat is returned so
+// the importer can hand them out to resume play.
+// Records longer than maxRecordSize are refused.
+func ImportHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRecordSize+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxRecordSize {
+		http.Error(w, "game record is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	g, err := addNewGame(func(id string) (*game.Game, error) {
+		return game.ReadRecord(bytes.NewReader(body), id, func() string { return uuid.New().String() })
+	})
+	if errors.Is(err, errNotSaved) {
+		writeMoveError(w, err)
+		return
+	}
+	if err != nil {
+		http.Error(w, "invalid game record: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	g.Lock()
+	defer g.Unlock()
+	type seat struct {
+		PlayerID    string `json:"playerId"`
+		DisplayName string `json:"displayName"`
+		Token       string `json:"token"`
+	}
+	seats := []seat{}
+	for _, p := range g.Players {
+		seats = append(seats, seat{PlayerID: p.ID, DisplayName: p.DisplayName, Token: p.Token})
+	}
+	resp := map[string]interface{}{
+		"gameId": g.ID,
+		"state":  g.State,
+		"seats":  seats,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/etanetan/up-and-down-the-river/backend/internal/game"
+)
+
+func TestExportRefusesUnfinishedGames(t *testing.T) {
+	w := post(CreateGameHandler, map[string]interface{}{"displayName": "Ann"})
+	var created map[string]string
+	json.NewDecoder(w.Body).Decode(&created)
+
+	w = httptest.NewRecorder()
+	ExportHandler(w, httptest.NewRequest(http.MethodGet, "/?gameId="+created["gameId"]+"&token="+created["token"], nil))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("export of a game in the lobby: got %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestImportRefusesOversizeRecords(t *testing.T) {
+	for _, tc := range []struct {
+		size int
+		want int
+	}{
+		{maxRecordSize, http.StatusBadRequest},
+		{maxRecordSize + 1, http.StatusRequestEntityTooLarge},
+	} {
+		body := bytes.Repeat([]byte("#"), tc.size)
+		w := httptest.NewRecorder()
+		ImportHandler(w, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)))
+		if w.Code != tc.want {
+			t.Errorf("record of %d bytes: got %d %s, want %d", tc.size, w.Code, strings.TrimSpace(w.Body.String()), tc.want)
+		}
+	}
+}
+
+func TestNewGameIDCollision(t *testing.T) {
+	var first map[string]string
+	json.NewDecoder(post(CreateGameHandler, map[string]interface{}{"displayName": "Ann"}).Body).Decode(&first)
+	taken := first["gameId"]
+	post(JoinGameHandler, map[string]string{"gameId": taken, "displayName": "Cy"})
+
+	ids := []string{taken, taken, "ZZZ998"}
+	newGameID = func() string {
+		id := ids[0]
+		ids = ids[1:]
+		return id
+	}
+	defer func() { newGameID = generateGameID }()
+	w := post(CreateGameHandler, map[string]interface{}{"displayName": "Bob"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("create with a taken ID: %d %s", w.Code, w.Body)
+	}
+	var second map[string]string
+	json.NewDecoder(w.Body).Decode(&second)
+	if second["gameId"] != "ZZZ998" {
+		t.Fatalf("new game got ID %q, want ZZZ998", second["gameId"])
+	}
+
+	g, _ := game.GetGame(taken)
+	g.Lock()
+	name := g.Players[0].DisplayName
+	events, ok := eventsSince(g, 1)
+	g.Unlock()
+	if name != "Ann" || !ok || len(events) == 0 {
+		t.Fatalf("game %s now has %q seated and %d kept events", taken, name, len(events))
+	}
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"log"
+	"time"
+
+	"github.com/etanetan/up-and-down-the-river/backend/internal/game"
+)
+
+// scheduleReviewEnd arranges for the review g is in to end at its ReviewDeadline,
+// unless the seats acknowledge it or the game moves on some other way first.
+// The caller must hold g's lock.
+func scheduleReviewEnd(g *game.Game) {
+	deadline := g.ReviewDeadline
+	time.AfterFunc(time.Until(deadline), func() {
+		g.Lock()
+		defer g.Unlock()
+		if !g.InReview() || !g.ReviewDeadline.Equal(deadline) {
+			// This review is already over.
+			return
+		}
+		if err := applyAction(g, game.Action{Type: game.ActionAdvance}); err != nil {
+			log.Printf("game %s: ending review: %v", g.ID, err)
+		}
+	})
+}
+
+// ResumeReviews schedules the end of every stored game's trick or round review,
+// so reviews that were running when the server stopped still end.
+// It should be called once at startup, after game.Store is set.
+func ResumeReviews() error {
+	ids, err := game.Store.List()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		g, ok := game.GetGame(id)
+		if !ok {
+			continue
+		}
+		g.Lock()
+		if g.InReview() && !g.ReviewDeadline.IsZero() {
+			scheduleReviewEnd(g)
+		}
+		g.Unlock()
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/etanetan/up-and-down-the-river/backend/internal/game"
+)
+
+// sseKeepAlive is how often a comment is sent to keep idle proxies from closing the stream.
+const sseKeepAlive = 15 * time.Second
+
+// EventsHandler streams game events as Server-Sent Events.
+// Each event is sent with its type as the SSE event name, and the last event
+// of every action carries the game version it produced as the SSE id.
+// A reconnecting client resumes from the Last-Event-ID header (or the
+// lastEventId query parameter) and first receives the events it missed.
+// If those are no longer kept, it gets a single "resync" event instead,
+// carrying the game state as it may see it, and should start over from there.
+// If a valid token is given, every batch of events is followed by a "state"
+// event carrying that seat's redacted game state.
+func EventsHandler(w http.ResponseWriter, r *http.Request) {
+	gameID := r.URL.Query().Get("gameId")
+	if gameID == "" {
+		http.Error(w, "gameId required", http.StatusBadRequest)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("lastEventId")
+	}
+	var lastSeq int
+	if lastID != "" {
+		seq, err := strconv.Atoi(lastID)
+		if err != nil {
+			http.Error(w, "invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+		lastSeq = seq
+	}
+
+	g, ok := game.GetGame(gameID)
+	if !ok {
+		http.Error(w, "game not found", http.StatusNotFound)
+		return
+	}
+	g.Lock()
+	var viewerID string
+	if p, _ := game.FindPlayerByToken(g, r.URL.Query().Get("token")); p != nil {
+		viewerID = p.ID
+	}
+	sub := subscribe(g, viewerID)
+	missed, complete := eventsSince(g, lastSeq)
+	resync := lastID != "" && !complete
+	snapshot := game.NewGameView(g, viewerID)
+	g.Unlock()
+	defer func() {
+		g.Lock()
+		unsubscribe(g, sub)
+		g.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	if resync {
+		if err := writeSSE(w, strconv.Itoa(snapshot.Version), "resync", snapshot); err != nil {
+			return
+		}
+	}
+	for i, ev := range missed {
+		var id string
+		if i == len(missed)-1 || missed[i+1].Seq != ev.Seq {
+			id = strconv.Itoa(ev.Seq)
+		}
+		if err := writeSSE(w, id, ev.Type, ev); err != nil {
+			return
+		}
+	}
+	if viewerID != "" && !resync {
+		if err := writeSSE(w, "", "state", snapshot); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case u, ok := <-sub.send:
+			if !ok {
+				return
+			}
+			var id string
+			if u.Last {
+				id = strconv.Itoa(u.Event.Seq)
+			}
+			if err := writeSSE(w, id, u.Event.Type, u.Event); err != nil {
+				return
+			}
+			// Only the latest state matters, so skip it while more events are queued.
+			if viewerID != "" && len(sub.send) == 0 {
+				if err := writeSSE(w, "", "state", u.State); err != nil {
+					return
+				}
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// writeSSE writes one Server-Sent Event with data encoded as JSON.
+// An empty id leaves the client's last event ID unchanged.
+func writeSSE(w http.ResponseWriter, id, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if id != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	return err
+}
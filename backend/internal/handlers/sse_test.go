@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/etanetan/up-and-down-the-river/backend/internal/game"
+)
+
+// connectSSE opens the event stream of a game, resuming after lastEventID,
+// and returns what is sent before any new event arrives.
+func connectSSE(gameID, token, lastEventID string) string {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodGet, "/?gameId="+gameID+"&token="+token, nil).WithContext(ctx)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	w := httptest.NewRecorder()
+	EventsHandler(w, req)
+	return w.Body.String()
+}
+
+func TestEventsResync(t *testing.T) {
+	var created map[string]string
+	json.NewDecoder(post(CreateGameHandler, map[string]interface{}{"displayName": "Ann"}).Body).Decode(&created)
+	gameID := created["gameId"]
+	post(JoinGameHandler, map[string]string{"gameId": gameID, "displayName": "Bob"})
+	post(JoinGameHandler, map[string]string{"gameId": gameID, "displayName": "Cy"})
+
+	if got := connectSSE(gameID, "", "1"); strings.Contains(got, "resync") || strings.Count(got, "event: playerJoined") != 2 {
+		t.Fatalf("catching up from version 1:\n%s", got)
+	}
+	if got := connectSSE(gameID, "", "3"); got != "" {
+		t.Fatalf("catching up from the current version:\n%s", got)
+	}
+
+	// Reloading the game from the store drops the events kept for it.
+	g, _ := game.GetGame(gameID)
+	g.Lock()
+	forgetGame(g)
+	g.Unlock()
+	for _, token := range []string{"", created["token"]} {
+		got := connectSSE(gameID, token, "1")
+		if !strings.Contains(got, "id: 3\nevent: resync\n") || strings.Contains(got, "event: state") {
+			t.Fatalf("catching up with token %q after a reload:\n%s", token, got)
+		}
+	}
+	if got := connectSSE(gameID, "", "3"); got != "" {
+		t.Fatalf("catching up from the current version after a reload:\n%s", got)
+	}
+}
+
+func TestEventHistoryKeepsWholeActions(t *testing.T) {
+	g := game.NewGame(game.Config{ID: "history-test"})
+	g.Lock()
+	defer g.Unlock()
+	defer forgetGame(g)
+	for v := 1; v <= 300; v++ {
+		g.Version = v
+		broadcast(g, game.Event{Type: game.EventCardPlayed}, game.Event{Type: game.EventTrickWon}, game.Event{Type: game.EventTrickStarted})
+	}
+	hubMu.Lock()
+	kept := history[g.ID]
+	hubMu.Unlock()
+	if len(kept) > historyLimit || len(kept)%3 != 0 {
+		t.Fatalf("kept %d events", len(kept))
+	}
+	oldest := kept[0].Seq
+	if events, ok := eventsSince(g, oldest-1); !ok || len(events) != len(kept) {
+		t.Fatalf("from version %d: %d events, complete %t", oldest-1, len(events), ok)
+	}
+	if _, ok := eventsSince(g, oldest-2); ok {
+		t.Fatalf("from version %d: events are missing but reported complete", oldest-2)
+	}
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/etanetan/up-and-down-the-river/backend/internal/game"
+	"github.com/gorilla/websocket"
+)
+
+// The API is served to any origin (see withCORS), so the socket is too.
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// Limits on a socket: commands are tiny, and a client that stops answering
+// pings is dropped along with its subscription.
+const (
+	wsMaxCommandSize = 1024
+	wsPongWait       = 60 * time.Second
+	wsPingPeriod     = wsPongWait * 9 / 10
+	wsWriteWait      = 10 * time.Second
+)
+
+// wsMessage is a message sent from the server over the socket.
+// Type is "snapshot" for the initial state, "event" for a game event,
+// or "error" when a bid or play sent over the socket was rejected.
+type wsMessage struct {
+	Type  string         `json:"type"`
+	Event *game.Event    `json:"event,omitempty"`
+	State *game.GameView `json:"state,omitempty"`
+	Error string         `json:"error,omitempty"`
+}
+
+// wsCommand is a message sent from the client over the socket.
+// Type is "look", "bid", "play" or "ack"; Blind marks a blind bid.
+type wsCommand struct {
+	Type  string    `json:"type"`
+	Bid   int       `json:"bid"`
+	Blind bool      `json:"blind"`
+	Card  game.Card `json:"card"`
+}
+
+// WebSocketHandler subscribes a seat to live updates for its game.
+// The seat is identified by the gameId and token query parameters. After an
+// initial snapshot, every game event is pushed together with the seat's
+// redacted state, and the client may send bids and plays on the same socket.
+func WebSocketHandler(w http.ResponseWriter, r *http.Request) {
+	gameID := r.URL.Query().Get("gameId")
+	if gameID == "" {
+		http.Error(w, "gameId required", http.StatusBadRequest)
+		return
+	}
+	g, ok := game.GetGame(gameID)
+	if !ok {
+		http.Error(w, "game not found", http.StatusNotFound)
+		return
+	}
+	player := authenticate(w, g, r.URL.Query().Get("token"))
+	if player == nil {
+		return
+	}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already written the error response.
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(wsMaxCommandSize)
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	g.Lock()
+	sub := subscribe(g, player.ID)
+	snapshot := game.NewGameView(g, player.ID)
+	g.Unlock()
+	defer func() {
+		g.Lock()
+		unsubscribe(g, sub)
+		g.Unlock()
+	}()
+
+	rejected := make(chan string, 8)
+	go func() {
+		defer conn.Close()
+		ping := time.NewTicker(wsPingPeriod)
+		defer ping.Stop()
+		conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+		if err := conn.WriteJSON(wsMessage{Type: "snapshot", State: snapshot}); err != nil {
+			return
+		}
+		for {
+			var msg wsMessage
+			select {
+			case u, ok := <-sub.send:
+				if !ok {
+					return
+				}
+				msg = wsMessage{Type: "event", Event: &u.Event, State: u.State}
+			case reason := <-rejected:
+				msg = wsMessage{Type: "error", Error: reason}
+			case <-ping.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+					return
+				}
+				continue
+			}
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		var cmd wsCommand
+		if err := conn.ReadJSON(&cmd); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				log.Printf("websocket %s/%s: %v", g.ID, player.ID, err)
+			}
+			return
+		}
+		switch cmd.Type {
+		case "look":
+			g.Lock()
+			err = applyAction(g, game.Action{Type: game.ActionLook, PlayerID: player.ID})
+			g.Unlock()
+		case "bid":
+			g.Lock()
+			err = applyAction(g, game.Action{Type: game.ActionBid, PlayerID: player.ID, Bid: cmd.Bid, Blind: cmd.Blind})
+			g.Unlock()
+		case "play":
+			g.Lock()
+			err = applyAction(g, game.Action{Type: game.ActionPlay, PlayerID: player.ID, Card: cmd.Card})
+			g.Unlock()
+		case "ack":
+			g.Lock()
+			err = applyAction(g, game.Action{Type: game.ActionAck, PlayerID: player.ID})
+			g.Unlock()
+		default:
+			err = errors.New("unknown command type")
+		}
+		if err != nil {
+			select {
+			case rejected <- err.Error():
+			default:
+			}
+		}
+	}
+}
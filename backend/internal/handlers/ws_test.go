@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestWebSocketReadLimit(t *testing.T) {
+	var created map[string]string
+	json.NewDecoder(post(CreateGameHandler, map[string]interface{}{"displayName": "Ann"}).Body).Decode(&created)
+	srv := httptest.NewServer(http.HandlerFunc(WebSocketHandler))
+	defer srv.Close()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/?gameId=" + created["gameId"] + "&token=" + created["token"]
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var msg wsMessage
+	if err := conn.ReadJSON(&msg); err != nil || msg.Type != "snapshot" {
+		t.Fatalf("first message: %+v, %v", msg, err)
+	}
+	// A small command is read and answered.
+	if err := conn.WriteJSON(wsCommand{Type: "bid"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.ReadJSON(&msg); err != nil || msg.Type != "error" {
+		t.Fatalf("answer to a bid in the lobby: %+v, %v", msg, err)
+	}
+	// One over the limit closes the socket.
+	big := `{"type":"bid","pad":"` + strings.Repeat("x", wsMaxCommandSize) + `"}`
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(big)); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseMessageTooBig) {
+		t.Fatalf("after an oversize command: got %v, want the socket closed as too big", err)
+	}
+}
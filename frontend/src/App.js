@@ -66,6 +66,47 @@ const formatCard = (card) => {
 	);
 };
 
+const suitSymbols = { spades: '♠', hearts: '♥', diamonds: '♦', clubs: '♣' };
+
+const formatTrump = (round, breakTrump) => {
+	const trump = round.trump ? `Trump: ${suitSymbols[round.trump]}` : 'No trump';
+	return (
+		<span>
+			{trump}
+			{round.turnUp && <> (turned up {formatCard(round.turnUp)})</>}
+			{breakTrump && round.trump && (round.trumpBroken ? ' (broken)' : ' (not broken)')}
+		</span>
+	);
+};
+
+// leadSuitOf returns the suit players must follow, or '' if there is none.
+// A joker led calls for trump, for other jokers when they are their own
+// suit, and for nothing when it is wild.
+const leadSuitOf = (round, jokers) => {
+	const led = round.currentTrick.plays[0].card;
+	if (led.rank <= 14) return led.suit.toLowerCase();
+	if (jokers === 'suit') return 'jokers';
+	if (jokers === 'wild') return '';
+	return round.trump || '';
+};
+
+// followsLead mirrors the server's follow-suit rule: trump jokers never
+// follow, wild jokers always do.
+const followsLead = (card, leadSuit, jokers) => {
+	if (card.rank > 14) {
+		return jokers === 'wild' || (jokers === 'suit' && leadSuit === 'jokers');
+	}
+	return card.suit.toLowerCase() === leadSuit;
+};
+
+// inTrump mirrors the server's idea of a trump card: the trump suit, plus
+// the jokers when they are trumps.
+const inTrump = (card, trump, jokers) => {
+	if (!trump) return false;
+	if (card.rank > 14) return jokers === 'trump';
+	return card.suit.toLowerCase() === trump;
+};
+
 const sortHand = (hand) => {
 	const suitOrder = { diamonds: 1, clubs: 2, hearts: 3, spades: 4 };
 	return hand.slice().sort((a, b) => {
@@ -175,7 +216,8 @@ function Scoreboard({ gameState }) {
 												key={player.id}
 												style={{ color: hit ? 'green' : 'red' }}
 											>
-												{result.roundScore} ({result.bid})
+												{result.roundScore} ({result.bid}
+												{result.blind ? ' blind' : ''})
 											</td>
 										);
 									} else {
@@ -189,11 +231,15 @@ function Scoreboard({ gameState }) {
 							<td>{gameState.currentRound.totalCards}</td>
 							{gameState.players.map((player) => {
 								const bid = gameState.currentRound.bids[player.id];
-								return (
-									<td key={player.id}>
-										{bid !== undefined ? `(${bid})` : '(-)'}
-									</td>
-								);
+								const blind = gameState.currentRound.blindBids?.[player.id];
+								const sealed = gameState.currentRound.sealed?.[player.id];
+								let shown = '(-)';
+								if (bid !== undefined) {
+									shown = `(${bid}${blind ? ' blind' : ''})`;
+								} else if (sealed) {
+									shown = '(sealed)';
+								}
+								return <td key={player.id}>{shown}</td>;
 							})}
 						</tr>
 					)}
@@ -222,15 +268,35 @@ function Scoreboard({ gameState }) {
 function App() {
 	const [view, setView] = useState('home');
 	const [gameId, setGameId] = useState('');
-	const [playerId, setPlayerId] = useState('');
+	const [token, setToken] = useState('');
 	const [displayName, setDisplayName] = useState('');
 	const [creatorMaxCards, setCreatorMaxCards] = useState(10);
+	const [trumpRule, setTrumpRule] = useState('fixed');
+	const [trumpSuit, setTrumpSuit] = useState('spades');
+	const [jokers, setJokers] = useState('trump');
+	const [scoring, setScoring] = useState('squared');
+	const [formula, setFormula] = useState('made ? 10 + bid * bid : 0');
+	const [sequence, setSequence] = useState('upDown');
+	const [fixedRounds, setFixedRounds] = useState(10);
+	const [customRounds, setCustomRounds] = useState('1 2 3 2 1');
+	const [bidRule, setBidRule] = useState('dealer');
+	const [leadRule, setLeadRule] = useState('highestBidder');
+	const [decks, setDecks] = useState(1);
+	const [ties, setTies] = useState('firstPlayed');
+	const [breakTrump, setBreakTrump] = useState(false);
+	const [blindBids, setBlindBids] = useState(false);
+	const [sealedBids, setSealedBids] = useState(false);
+	const [seed, setSeed] = useState('');
+	const [createError, setCreateError] = useState('');
 	const [gameState, setGameState] = useState(null);
 	const [selectedCard, setSelectedCard] = useState(null);
 	const [lastTrick, setLastTrick] = useState(null);
 	const [gameOver, setGameOver] = useState(false);
 	const [showMobileScoreboard, setShowMobileScoreboard] = useState(false);
 
+	// The server identifies us in the game state by our public seat handle.
+	const mySeat = gameState ? gameState.seat : '';
+
 	// Use the custom hook once
 	const windowWidth = useWindowWidth();
 
@@ -250,9 +316,12 @@ function App() {
 	const isMyTurnToBid = () => {
 		if (!gameState || !gameState.currentRound) return false;
 		const round = gameState.currentRound;
+		if (gameState.config.sealedBids && !round.rebid) {
+			return round.bids[mySeat] === undefined;
+		}
 		return (
 			normalizeId(round.bidOrder[round.currentBidTurn]) ===
-			normalizeId(playerId)
+			normalizeId(mySeat)
 		);
 	};
 
@@ -261,7 +330,7 @@ function App() {
 			if (view !== 'game' || !gameState || gameState.state !== 'playing')
 				return;
 			const me = gameState.players.find(
-				(p) => normalizeId(p.id) === normalizeId(playerId)
+				(p) => normalizeId(p.id) === normalizeId(mySeat)
 			);
 			if (!me || !me.hand || me.hand.length === 0) return;
 			const sortedHand = sortHand(me.hand);
@@ -297,17 +366,47 @@ function App() {
 		};
 		window.addEventListener('keydown', handleKeyDown);
 		return () => window.removeEventListener('keydown', handleKeyDown);
-	}, [view, gameState, playerId, selectedCard]);
+	}, [view, gameState, mySeat, selectedCard]);
 
 	const createGame = async () => {
 		const response = await fetch(`${API_URL}/games/create`, {
 			method: 'POST',
 			headers: { 'Content-Type': 'application/json' },
-			body: JSON.stringify({ displayName, creatorMaxCards }),
+			body: JSON.stringify({
+				displayName,
+				creatorMaxCards,
+				trumpRule,
+				trumpSuit,
+				jokers,
+				scoring,
+				formula: scoring === 'formula' ? formula : '',
+				sequence,
+				fixedRounds: sequence === 'fixed' ? fixedRounds : 0,
+				rounds:
+					sequence === 'custom'
+						? customRounds
+								.split(/[\s,]+/)
+								.filter((n) => n !== '')
+								.map((n) => parseInt(n, 10))
+						: [],
+				bidRule,
+				leadRule,
+				decks,
+				ties,
+				breakTrump,
+				blindBids,
+				sealedBids,
+				seed: seed.trim() || undefined,
+			}),
 		});
+		if (!response.ok) {
+			setCreateError(await response.text());
+			return;
+		}
+		setCreateError('');
 		const data = await response.json();
 		setGameId(data.gameId);
-		setPlayerId(data.playerId);
+		setToken(data.token);
 		setView('lobby');
 	};
 
@@ -322,7 +421,7 @@ function App() {
 			body: JSON.stringify({ gameId, displayName }),
 		});
 		const data = await response.json();
-		setPlayerId(data.playerId);
+		setToken(data.token);
 		setView('lobby');
 	};
 
@@ -330,7 +429,26 @@ function App() {
 		await fetch(`${API_URL}/games/start`, {
 			method: 'POST',
 			headers: { 'Content-Type': 'application/json' },
-			body: JSON.stringify({ gameId }),
+			body: JSON.stringify({ gameId, token }),
+		});
+		fetchGameState();
+	};
+
+	// handHidden reports whether my hand is still face down, waiting for me
+	// to look at it or bid blind.
+	const handHidden = () => {
+		if (!gameState || gameState.state !== 'bidding') return false;
+		const me = gameState.players.find(
+			(p) => normalizeId(p.id) === normalizeId(mySeat)
+		);
+		return !!me && me.cardCount > 0 && !me.hand;
+	};
+
+	const handleLook = async () => {
+		await fetch(`${API_URL}/games/look`, {
+			method: 'POST',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify({ gameId, token }),
 		});
 		fetchGameState();
 	};
@@ -339,7 +457,12 @@ function App() {
 		await fetch(`${API_URL}/games/bid`, {
 			method: 'POST',
 			headers: { 'Content-Type': 'application/json' },
-			body: JSON.stringify({ gameId, playerId, bid: parseInt(bidValue, 10) }),
+			body: JSON.stringify({
+				gameId,
+				token,
+				bid: parseInt(bidValue, 10),
+				blind: handHidden(),
+			}),
 		});
 		fetchGameState();
 	};
@@ -349,13 +472,15 @@ function App() {
 		await fetch(`${API_URL}/games/play`, {
 			method: 'POST',
 			headers: { 'Content-Type': 'application/json' },
-			body: JSON.stringify({ gameId, playerId, card: selectedCard }),
+			body: JSON.stringify({ gameId, token, card: selectedCard }),
 		});
 		setSelectedCard(null);
 	};
 
 	const fetchGameState = async () => {
-		const response = await fetch(`${API_URL}/games/state?gameId=${gameId}`);
+		const response = await fetch(
+			`${API_URL}/games/state?gameId=${gameId}&token=${token}`
+		);
 		if (!response.ok) {
 			const errorText = await response.text();
 			console.error('Error fetching game state:', errorText);
@@ -381,7 +506,8 @@ function App() {
 			if (
 				data.state === 'bidding' ||
 				data.state === 'playing' ||
-				data.state === 'scoring'
+				data.state === 'trickReview' ||
+				data.state === 'roundReview'
 			) {
 				setView('game');
 			}
@@ -392,7 +518,7 @@ function App() {
 		if (!gameState || !gameState.currentRound) return '';
 		const round = gameState.currentRound;
 		if (round.currentTrick && round.currentTrick.winnerID) {
-			if (normalizeId(round.currentTrick.winnerID) === normalizeId(playerId)) {
+			if (normalizeId(round.currentTrick.winnerID) === normalizeId(mySeat)) {
 				return 'YOU won the trick!';
 			} else {
 				const winner = gameState.players.find(
@@ -401,9 +527,21 @@ function App() {
 				return winner ? `${winner.displayName} won the trick!` : '';
 			}
 		}
+		if (
+			gameState.state === 'bidding' &&
+			gameState.config.sealedBids &&
+			!round.rebid
+		) {
+			if (round.bids[mySeat] === undefined) {
+				return 'YOUR TURN to bid (bids are sealed)';
+			}
+			const waiting =
+				gameState.players.length - Object.keys(round.sealed || {}).length;
+			return `Waiting for ${waiting} more sealed bid${waiting === 1 ? '' : 's'}`;
+		}
 		if (gameState.state === 'bidding') {
 			const currentBidderId = round.bidOrder[round.currentBidTurn];
-			if (normalizeId(currentBidderId) === normalizeId(playerId)) {
+			if (normalizeId(currentBidderId) === normalizeId(mySeat)) {
 				return 'YOUR TURN to bid';
 			} else {
 				const currentBidder = gameState.players.find(
@@ -421,7 +559,7 @@ function App() {
 				const currentPlayerIndex =
 					(round.trickLeader + round.trickTurnIndex) % gameState.players.length;
 				const currentPlayer = gameState.players[currentPlayerIndex];
-				if (normalizeId(currentPlayer.id) === normalizeId(playerId)) {
+				if (normalizeId(currentPlayer.id) === normalizeId(mySeat)) {
 					return 'YOUR TURN to play a card';
 				} else {
 					return currentPlayer
@@ -437,7 +575,7 @@ function App() {
 		await fetch(`${API_URL}/games/reset`, {
 			method: 'POST',
 			headers: { 'Content-Type': 'application/json' },
-			body: JSON.stringify({ gameId }),
+			body: JSON.stringify({ gameId, token }),
 		});
 		setTimeout(() => {
 			fetchGameState();
@@ -449,7 +587,7 @@ function App() {
 			if (gameId) fetchGameState();
 		}, 2000);
 		return () => clearInterval(interval);
-	}, [gameId]);
+	}, [gameId, token]);
 
 	const renderMobileScoreboardToggle = () => (
 		<div
@@ -529,7 +667,7 @@ function App() {
 	const renderGameBoard = () => {
 		if (!gameState) return <div>Loading game state...</div>;
 		const me = gameState.players.find(
-			(p) => normalizeId(p.id) === normalizeId(playerId)
+			(p) => normalizeId(p.id) === normalizeId(mySeat)
 		);
 		const round = gameState.currentRound;
 		const sortedHand = me && me.hand ? sortHand(me.hand) : [];
@@ -560,6 +698,12 @@ function App() {
 									))}
 							</tbody>
 						</table>
+						{gameState.config.seed && (
+							<p>
+								Deal seed: {gameState.config.seed}. Create a game with it to
+								play the same cards.
+							</p>
+						)}
 						<button className="play-again-button" onClick={resetGame}>
 							Play Again
 						</button>
@@ -568,6 +712,9 @@ function App() {
 				<div className="top-section">
 					<div className="action-message">
 						<p>{turnMessage}</p>
+						{round && gameState.state !== 'lobby' && (
+							<p>{formatTrump(round, gameState.config.breakTrump)}</p>
+						)}
 					</div>
 				</div>
 				{windowWidth < 768 && (
@@ -598,7 +745,7 @@ function App() {
 							<TablePlayers
 								players={gameState.players}
 								currentRound={round}
-								currentPlayerId={playerId}
+								currentPlayerId={mySeat}
 							/>
 						)}
 					</div>
@@ -613,13 +760,15 @@ function App() {
 				</div>
 				{gameState &&
 					gameState.state === 'bidding' &&
-					!gameState.currentRound.bids[playerId] &&
+					!gameState.currentRound.bids[mySeat] &&
 					(windowWidth < 768 ? (
 						<div className="bid-modal-container">
 							<BidModal
 								onPlaceBid={handlePlaceBid}
 								isMyTurn={isMyTurnToBid()}
 								maxBid={gameState.currentRound.totalCards}
+								forbiddenBid={gameState.currentRound.forbiddenBid}
+								blind={handHidden()}
 							/>
 						</div>
 					) : (
@@ -627,8 +776,16 @@ function App() {
 							onPlaceBid={handlePlaceBid}
 							isMyTurn={isMyTurnToBid()}
 							maxBid={gameState.currentRound.totalCards}
+							forbiddenBid={gameState.currentRound.forbiddenBid}
+							blind={handHidden()}
 						/>
 					))}
+				{handHidden() && (
+					<div className="look-section">
+						<p>Your hand is face down. Look at it, or bid blind for double points.</p>
+						<button onClick={handleLook}>Look at hand</button>
+					</div>
+				)}
 				{selectedCard && (
 					<div className="play-card-section">
 						<button
@@ -639,16 +796,27 @@ function App() {
 									gameState.state === 'playing' &&
 									(() => {
 										if (gameState.currentRound.currentTrick.plays.length > 0) {
-											const leadSuit =
-												gameState.currentRound.currentTrick.plays[0].card.suit.toLowerCase();
+											const jokers = gameState.config.jokers;
+											const leadSuit = leadSuitOf(gameState.currentRound, jokers);
+											if (!leadSuit || followsLead(selectedCard, leadSuit, jokers)) {
+												return true;
+											}
 											const hasLeadSuit = me.hand.some(
-												(c) => c.rank <= 14 && c.suit.toLowerCase() === leadSuit
+												(c) =>
+													!cardMatches(c, selectedCard) &&
+													!(c.rank > 14 && jokers === 'wild') &&
+													followsLead(c, leadSuit, jokers)
 											);
-											if (hasLeadSuit) {
-												if (selectedCard.suit.toLowerCase() !== leadSuit) {
-													return false;
-												}
-											}
+											return !hasLeadSuit;
+										}
+										const { trump, trumpBroken } = gameState.currentRound;
+										const jokers = gameState.config.jokers;
+										if (
+											gameState.config.breakTrump &&
+											!trumpBroken &&
+											inTrump(selectedCard, trump, jokers)
+										) {
+											return me.hand.every((c) => inTrump(c, trump, jokers));
 										}
 										return true;
 									})()
@@ -682,6 +850,132 @@ function App() {
 					value={creatorMaxCards}
 					onChange={(e) => setCreatorMaxCards(parseInt(e.target.value, 10))}
 				/>
+				<select value={trumpRule} onChange={(e) => setTrumpRule(e.target.value)}>
+					<option value="fixed">Fixed trump</option>
+					<option value="rotating">Rotating trump</option>
+					<option value="turnUp">Turn up trump</option>
+					<option value="none">No trump</option>
+				</select>
+				{(trumpRule === 'fixed' || trumpRule === 'rotating') && (
+					<select value={trumpSuit} onChange={(e) => setTrumpSuit(e.target.value)}>
+						<option value="spades">Spades</option>
+						<option value="hearts">Hearts</option>
+						<option value="diamonds">Diamonds</option>
+						<option value="clubs">Clubs</option>
+					</select>
+				)}
+				{trumpRule !== 'none' && (
+					<select
+						value={breakTrump ? 'yes' : 'no'}
+						onChange={(e) => setBreakTrump(e.target.value === 'yes')}
+					>
+						<option value="no">Trump may be led any time</option>
+						<option value="yes">Trump must be broken before it is led</option>
+					</select>
+				)}
+				<select value={jokers} onChange={(e) => setJokers(e.target.value)}>
+					<option value="trump">Jokers as top trumps</option>
+					<option value="suit">Jokers as their own suit</option>
+					<option value="wild">One wild joker</option>
+					<option value="none">No jokers</option>
+				</select>
+				<select value={scoring} onChange={(e) => setScoring(e.target.value)}>
+					<option value="squared">10 + bid squared</option>
+					<option value="classic">10 + bid</option>
+					<option value="tricks">1 per trick, 10 for exact bid</option>
+					<option value="penalty">Lose the difference on a miss</option>
+					<option value="bags">Spades-style bags</option>
+					<option value="formula">Custom formula</option>
+				</select>
+				{scoring === 'formula' && (
+					<input
+						type="text"
+						placeholder="e.g. made ? 10 + bid : -abs(bid - tricks)"
+						value={formula}
+						onChange={(e) => setFormula(e.target.value)}
+					/>
+				)}
+				<select value={sequence} onChange={(e) => setSequence(e.target.value)}>
+					<option value="upDown">Up and down</option>
+					<option value="up">Up only</option>
+					<option value="down">Down only</option>
+					<option value="downUp">Down and up</option>
+					<option value="doublePeak">Up and down, peak twice</option>
+					<option value="fixed">Fixed number of rounds at max cards</option>
+					<option value="custom">Custom rounds</option>
+				</select>
+				{sequence === 'fixed' && (
+					<input
+						type="number"
+						placeholder="Number of rounds"
+						value={fixedRounds}
+						onChange={(e) => setFixedRounds(parseInt(e.target.value, 10))}
+					/>
+				)}
+				{sequence === 'custom' && (
+					<input
+						type="text"
+						placeholder="Cards per round, e.g. 1 3 5 3 1"
+						value={customRounds}
+						onChange={(e) => setCustomRounds(e.target.value)}
+					/>
+				)}
+				<select
+					value={sealedBids ? 'sealed' : 'inTurn'}
+					onChange={(e) => {
+						const sealed = e.target.value === 'sealed';
+						setSealedBids(sealed);
+						setBidRule(sealed ? 'rebid' : 'dealer');
+					}}
+				>
+					<option value="inTurn">Bid in turn</option>
+					<option value="sealed">Sealed bids, revealed together</option>
+				</select>
+				{sealedBids ? (
+					<select value={bidRule} onChange={(e) => setBidRule(e.target.value)}>
+						<option value="rebid">Dealer bids again if bids add up (2+ cards)</option>
+						<option value="off">Any bid allowed</option>
+					</select>
+				) : (
+					<select value={bidRule} onChange={(e) => setBidRule(e.target.value)}>
+						<option value="dealer">Dealer can't make bids add up (2+ cards)</option>
+						<option value="always">Dealer can't make bids add up (every round)</option>
+						<option value="exceptPeak">Dealer can't make bids add up (except peak)</option>
+						<option value="lastBidder">Last bidder can't make bids add up</option>
+						<option value="off">Any bid allowed</option>
+					</select>
+				)}
+				<select
+					value={blindBids ? 'yes' : 'no'}
+					onChange={(e) => setBlindBids(e.target.value === 'yes')}
+				>
+					<option value="no">Hands dealt face up</option>
+					<option value="yes">Hands dealt face down (blind bids allowed)</option>
+				</select>
+				<select value={leadRule} onChange={(e) => setLeadRule(e.target.value)}>
+					<option value="highestBidder">Highest bidder leads</option>
+					<option value="lowestBidder">Lowest bidder leads</option>
+					<option value="leftOfDealer">Left of dealer leads</option>
+					<option value="dealer">Dealer leads</option>
+				</select>
+				<select value={decks} onChange={(e) => setDecks(parseInt(e.target.value, 10))}>
+					<option value={1}>One deck (up to 6 players)</option>
+					<option value={2}>Two decks (up to 10 players)</option>
+					<option value={3}>Three decks (up to 10 players)</option>
+				</select>
+				{decks > 1 && (
+					<select value={ties} onChange={(e) => setTies(e.target.value)}>
+						<option value="firstPlayed">Identical cards: first played wins</option>
+						<option value="lastPlayed">Identical cards: last played wins</option>
+					</select>
+				)}
+				<input
+					type="text"
+					placeholder="Deal seed (optional, for duplicate play)"
+					value={seed}
+					onChange={(e) => setSeed(e.target.value)}
+				/>
+				{createError && <p className="error-message">{createError}</p>}
 				<div className="button-group">
 					<button onClick={createGame}>Create Game</button>
 					<button onClick={joinGame}>Join Game</button>
@@ -716,6 +1010,9 @@ function App() {
 					{gameState &&
 						gameState.players.map((p) => <div key={p.id}>{p.displayName}</div>)}
 				</div>
+				{gameState && gameState.roundSequence && (
+					<p>Rounds: {gameState.roundSequence.join(' ')}</p>
+				)}
 				<button onClick={startGame}>Start Game</button>
 				<p>Waiting for game to start...</p>
 			</div>
@@ -11,9 +11,12 @@ import './BidModal.css';
  * - onPlaceBid: Function to call when the bid is placed.
  * - isMyTurn: Boolean indicating whether it's the player's turn to bid.
  * - maxBid: Maximum bid allowed (should be equal to the number of cards the player has).
+ * - forbiddenBid: A bid the rules do not allow right now, or undefined.
+ * - blind: Boolean indicating whether the bid is made without seeing the hand.
  */
-function BidModal({ onPlaceBid, isMyTurn, maxBid }) {
+function BidModal({ onPlaceBid, isMyTurn, maxBid, forbiddenBid, blind }) {
 	const [bid, setBid] = useState(0);
+	const isForbidden = bid === forbiddenBid;
 
 	// Increase bid (caps at maxBid)
 	const incrementBid = () => {
@@ -27,7 +30,7 @@ function BidModal({ onPlaceBid, isMyTurn, maxBid }) {
 
 	// Handle placing the bid
 	const handlePlaceBid = () => {
-		if (isMyTurn) onPlaceBid(bid);
+		if (isMyTurn && !isForbidden) onPlaceBid(bid);
 	};
 
 	// Handle keyboard input (including Enter to submit bid)
@@ -55,7 +58,7 @@ function BidModal({ onPlaceBid, isMyTurn, maxBid }) {
 
 		window.addEventListener('keydown', handleKeyDown);
 		return () => window.removeEventListener('keydown', handleKeyDown);
-	}, [isMyTurn, bid, maxBid]);
+	}, [isMyTurn, bid, maxBid, forbiddenBid]);
 
 	return (
 		<div className="bid-modal">
@@ -66,7 +69,9 @@ function BidModal({ onPlaceBid, isMyTurn, maxBid }) {
 			>
 				▲
 			</button>
-			<div className="bid-number">{bid}</div>
+			<div className={isForbidden ? 'bid-number forbidden' : 'bid-number'}>
+				{bid}
+			</div>
 			<button
 				className="arrow-button"
 				onClick={decrementBid}
@@ -77,9 +82,9 @@ function BidModal({ onPlaceBid, isMyTurn, maxBid }) {
 			<button
 				className="place-bid-button"
 				onClick={handlePlaceBid}
-				disabled={!isMyTurn}
+				disabled={!isMyTurn || isForbidden}
 			>
-				Bid
+				{blind ? 'Bid blind' : 'Bid'}
 			</button>
 		</div>
 	);